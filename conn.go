@@ -2,7 +2,10 @@ package dynamosql
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
+	"errors"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
@@ -15,9 +18,11 @@ type conn struct {
 	dynamo      dynamodbiface.DynamoDBAPI
 	tables      *schema.TableLoader
 	mapToGoType bool
+	// The current transaction, if any.
+	tx *tx
 }
 
-func (c conn) CheckNamedValue(value *driver.NamedValue) error {
+func (c *conn) CheckNamedValue(value *driver.NamedValue) error {
 	return nil
 }
 
@@ -25,17 +30,25 @@ var (
 	_ driver.Conn               = &conn{}
 	_ driver.ConnPrepareContext = &conn{}
 	_ driver.NamedValueChecker  = &conn{}
+	_ driver.ConnBeginTx        = &conn{}
 )
 
-func (c conn) Prepare(query string) (driver.Stmt, error) {
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
 	panic("unexpected call to Prepare")
 }
 
-func (c conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
-	ast, err := parser.Parse(query)
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	script, err := parser.ParseScript(query)
 	if err != nil {
 		return nil, err
 	}
+	if len(script.Statements) > 1 {
+		return &scriptStmt{conn: c, statements: script.Statements}, nil
+	}
+	return c.prepare(ctx, script.Statements[0].AST)
+}
+
+func (c *conn) prepare(ctx context.Context, ast *parser.AST) (driver.Stmt, error) {
 	switch {
 	case ast.Insert != nil:
 		stmt, err := querybuilder.PrepareInsert(ctx, c.tables, ast)
@@ -43,49 +56,172 @@ func (c conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, er
 			return nil, err
 		}
 		return &execStmt{
+			conn:         c,
+			preparedStmt: stmt,
+			dynamo:       c.dynamo,
+			mapToGoType:  c.mapToGoType,
+		}, nil
+	case ast.Update != nil:
+		stmt, err := querybuilder.PrepareUpdate(ctx, c.tables, ast)
+		if err != nil {
+			return nil, err
+		}
+		return &execStmt{
+			conn:         c,
+			preparedStmt: stmt,
+			dynamo:       c.dynamo,
+			mapToGoType:  c.mapToGoType,
+		}, nil
+	case ast.Delete != nil:
+		stmt, err := querybuilder.PrepareDelete(ctx, c.tables, ast)
+		if err != nil {
+			return nil, err
+		}
+		return &execStmt{
+			conn:         c,
+			preparedStmt: stmt,
+			dynamo:       c.dynamo,
+			mapToGoType:  c.mapToGoType,
+		}, nil
+	case ast.Check != nil:
+		stmt, err := querybuilder.PrepareCheck(ctx, c.tables, ast)
+		if err != nil {
+			return nil, err
+		}
+		return &execStmt{
+			conn:         c,
 			preparedStmt: stmt,
 			dynamo:       c.dynamo,
 			mapToGoType:  c.mapToGoType,
 		}, nil
 	case ast.Select != nil:
-		prepared, err := querybuilder.PrepareQuery(ctx, c.tables, query)
+		prepared, err := querybuilder.PrepareQueryAST(ctx, c.tables, ast)
 		if err != nil {
 			return nil, err
 		}
 		return &queryStmt{
+			conn:         c,
 			preparedStmt: prepared,
 			dynamo:       c.dynamo,
 			mapToGoType:  c.mapToGoType,
 		}, err
 	case ast.CreateTable != nil:
-		prepared, err := querybuilder.PrepareCreateTable(ast)
+		prepared, err := querybuilder.PrepareCreateTable(c.tables, ast)
 		if err != nil {
 			return nil, err
 		}
 		return &execStmt{
+			conn:         c,
+			preparedStmt: prepared,
+			dynamo:       c.dynamo,
+			mapToGoType:  c.mapToGoType,
+		}, err
+	case ast.AlterTable != nil:
+		prepared, err := querybuilder.PrepareAlterTable(c.tables, ast.AlterTable)
+		if err != nil {
+			return nil, err
+		}
+		return &execStmt{
+			conn:         c,
+			preparedStmt: prepared,
+			dynamo:       c.dynamo,
+			mapToGoType:  c.mapToGoType,
+		}, err
+	case ast.CreateIndex != nil:
+		prepared, err := querybuilder.PrepareCreateIndex(c.tables, ast.CreateIndex)
+		if err != nil {
+			return nil, err
+		}
+		return &execStmt{
+			conn:         c,
+			preparedStmt: prepared,
+			dynamo:       c.dynamo,
+			mapToGoType:  c.mapToGoType,
+		}, err
+	case ast.DropIndex != nil:
+		prepared, err := querybuilder.PrepareDropIndex(c.tables, ast.DropIndex)
+		if err != nil {
+			return nil, err
+		}
+		return &execStmt{
+			conn:         c,
 			preparedStmt: prepared,
 			dynamo:       c.dynamo,
 			mapToGoType:  c.mapToGoType,
 		}, err
 	case ast.DropTable != nil:
-		prepared, err := querybuilder.PrepareDropTable(ast.DropTable)
+		prepared, err := querybuilder.PrepareDropTable(c.tables, ast.DropTable)
 		if err != nil {
 			return nil, err
 		}
 		return &execStmt{
+			conn:         c,
 			preparedStmt: prepared,
 			dynamo:       c.dynamo,
 			mapToGoType:  c.mapToGoType,
 		}, err
+	case ast.ShowTables != nil:
+		prepared, err := querybuilder.PrepareShowTables(ast.ShowTables)
+		if err != nil {
+			return nil, err
+		}
+		return &showStmt{preparedStmt: prepared, dynamo: c.dynamo}, nil
+	case ast.ShowIndexes != nil:
+		prepared, err := querybuilder.PrepareShowIndexes(ast.ShowIndexes)
+		if err != nil {
+			return nil, err
+		}
+		return &showStmt{preparedStmt: prepared, dynamo: c.dynamo}, nil
+	case ast.ShowCreate != nil:
+		prepared, err := querybuilder.PrepareShowCreateTable(ast.ShowCreate)
+		if err != nil {
+			return nil, err
+		}
+		return &showStmt{preparedStmt: prepared, dynamo: c.dynamo}, nil
+	case ast.Describe != nil:
+		prepared, err := querybuilder.PrepareDescribe(ast.Describe)
+		if err != nil {
+			return nil, err
+		}
+		return &showStmt{preparedStmt: prepared, dynamo: c.dynamo}, nil
+	case ast.Explain != nil:
+		prepared, err := querybuilder.PrepareExplain(ctx, c.tables, ast.Explain)
+		if err != nil {
+			return nil, err
+		}
+		return &showStmt{preparedStmt: prepared, dynamo: c.dynamo}, nil
 	default:
 		panic("unsupported statement")
 	}
 }
 
-func (c conn) Close() error {
+func (c *conn) Close() error {
 	return nil
 }
 
-func (c conn) Begin() (driver.Tx, error) {
-	panic("implement me")
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx starts a transaction. Writes executed in the transaction are buffered, and submitted together with
+// TransactWriteItems on commit. In a read-only transaction, point SELECTs are made together with TransactGetItems.
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if c.tx != nil {
+		return nil, errors.New("already in a transaction")
+	}
+	switch sql.IsolationLevel(opts.Isolation) {
+	case sql.LevelDefault, sql.LevelSerializable:
+	default:
+		return nil, fmt.Errorf("isolation level %s is not supported, transactions are always serializable", sql.IsolationLevel(opts.Isolation))
+	}
+	if opts.ReadOnly {
+		c.tx = &tx{conn: c, ctx: ctx, reads: querybuilder.NewReadTransaction()}
+		return c.tx, nil
+	}
+	writes, err := querybuilder.NewTransaction()
+	if err != nil {
+		return nil, err
+	}
+	c.tx = &tx{conn: c, ctx: ctx, writes: writes}
+	return c.tx, nil
 }
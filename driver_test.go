@@ -2,9 +2,12 @@ package dynamosql
 
 import (
 	"bufio"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
@@ -301,6 +304,724 @@ func TestCreateDropTable(t *testing.T) {
 		) PROVISIONED THROUGHPUT READ 1 WRITE 1
     `)
 	require.NoError(t, err)
+	_, err = db.Exec(`DROP TABLE movies WAIT`)
+	require.NoError(t, err)
+	_, err = db.Exec(`DROP TABLE IF EXISTS movies`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS movies (title STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WITH (STREAM = NEW_IMAGE, TTL = expires_at, TAGS = {team: 'movies'}) WAIT`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO movies VALUES ('{"title": "Big"}')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS movies (title STRING HASH KEY) BILLING MODE PAY_PER_REQUEST`)
+	require.NoError(t, err)
+	ttl, err := client.DescribeTimeToLive(&dynamodb.DescribeTimeToLiveInput{TableName: aws.String("movies")})
+	require.NoError(t, err)
+	require.Equal(t, "expires_at", aws.StringValue(ttl.TimeToLiveDescription.AttributeName))
 	_, err = db.Exec(`DROP TABLE movies`)
 	require.NoError(t, err)
 }
+
+func TestRecreateTable(t *testing.T) {
+	sess := fixtures.SetUp(t)
+	client := dynamodb.New(sess)
+	_, _ = client.DeleteTable(&dynamodb.DeleteTableInput{TableName: aws.String("movies")})
+	defer func() {
+		_, _ = client.DeleteTable(&dynamodb.DeleteTableInput{TableName: aws.String("movies")})
+	}()
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+	err = db.Ping()
+	require.NoError(t, err)
+
+	// Load the table schema before dropping it
+	_, err = db.Exec(`CREATE TABLE movies (title STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WAIT`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO movies VALUES ({title: "Big"})`)
+	require.NoError(t, err)
+	var title string
+	err = db.QueryRow(`SELECT title FROM movies WHERE title = "Big"`).Scan(&title)
+	require.NoError(t, err)
+	require.Equal(t, "Big", title)
+
+	_, err = db.Exec(`DROP TABLE movies WAIT`)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE movies (id STRING HASH KEY, year NUMBER RANGE KEY) BILLING MODE PAY_PER_REQUEST WAIT`)
+	require.NoError(t, err)
+
+	// The new key schema is used, since the cached schema was dropped
+	_, err = db.Exec(`INSERT INTO movies VALUES ({id: "big", year: 1988})`)
+	require.NoError(t, err)
+	var id string
+	err = db.QueryRow(`SELECT id FROM movies WHERE id = "big" AND year = 1988`).Scan(&id)
+	require.NoError(t, err)
+	require.Equal(t, "big", id)
+}
+
+func TestAlterTable(t *testing.T) {
+	sess := fixtures.SetUp(t, fixtures.Movies)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+	err = db.Ping()
+	require.NoError(t, err)
+
+	// Load the table schema before altering it
+	_, err = db.Exec(`UPDATE movies SET director = "Penny Marshall" WHERE title = "Big" AND year = 1988`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`
+		ALTER TABLE movies
+			ADD director STRING,
+			ADD GLOBAL SECONDARY INDEX director_index
+				HASH(director)
+				PROJECTION KEYS_ONLY
+				PROVISIONED THROUGHPUT READ 1 WRITE 1,
+			SET PROVISIONED THROUGHPUT READ 2 WRITE 2
+	`)
+	require.NoError(t, err)
+
+	// The new index can be queried, since the cached schema was dropped
+	var title string
+	err = db.QueryRow(`SELECT title FROM movies USE INDEX (director_index) WHERE director = "Penny Marshall"`).Scan(&title)
+	require.NoError(t, err)
+	require.Equal(t, "Big", title)
+
+	_, err = db.Exec(`ALTER TABLE movies DROP INDEX director_index WAIT`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`CREATE GLOBAL INDEX director_title ON movies HASH(director STRING) RANGE(title) PROJECTION KEYS_ONLY PROVISIONED THROUGHPUT READ 1 WRITE 1 WAIT`)
+	require.NoError(t, err)
+	err = db.QueryRow(`SELECT title FROM movies USE INDEX (director_title) WHERE director = "Penny Marshall"`).Scan(&title)
+	require.NoError(t, err)
+	require.Equal(t, "Big", title)
+	_, err = db.Exec(`DROP INDEX director_title ON movies WAIT`)
+	require.NoError(t, err)
+}
+
+func TestShow(t *testing.T) {
+	sess := fixtures.SetUp(t, fixtures.GameScores)
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+
+	var tables []string
+	rows, err := db.Query(`SHOW TABLES LIKE 'game%'`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		tables = append(tables, name)
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, []string{"gamescores"}, tables)
+
+	var (
+		name, status, hashKey, rangeKey string
+		itemCount                       int64
+	)
+	rows, err = db.Query(`DESCRIBE gamescores`)
+	require.NoError(t, err)
+	cols, err := rows.Columns()
+	require.NoError(t, err)
+	require.Equal(t, "ttl_status", cols[len(cols)-1])
+	_ = rows.Close()
+	err = db.QueryRow(`DESCRIBE gamescores`).Scan(&name, &status, &hashKey, &rangeKey,
+		new(string), new(string), new(sql.NullInt64), new(sql.NullInt64), &itemCount, new(int64),
+		new(sql.NullString), new(sql.NullString), new(sql.NullString))
+	require.NoError(t, err)
+	require.Equal(t, []string{"gamescores", "ACTIVE", "UserId", "GameTitle"}, []string{name, status, hashKey, rangeKey})
+
+	var (
+		typ, projection string
+		indexStatus     sql.NullString
+		backfilling     sql.NullBool
+	)
+	err = db.QueryRow(`SHOW INDEXES FROM gamescores`).Scan(&name, &typ, &hashKey, &rangeKey, &projection, &indexStatus, &backfilling)
+	require.NoError(t, err)
+	require.Equal(t, []string{"GameTitleIndex", "GLOBAL", "GameTitle", "TopScore"}, []string{name, typ, hashKey, rangeKey})
+
+	_, err = db.Exec(`DESCRIBE gamescores`)
+	require.Error(t, err)
+
+	// The statement creates a copy of the table
+	var statement, copied string
+	err = db.QueryRow(`SHOW CREATE TABLE gamescores`).Scan(&name, &statement)
+	require.NoError(t, err)
+	_, err = db.Exec(strings.Replace(statement, "CREATE TABLE gamescores", "CREATE TABLE gamescores_copy", 1) + " WAIT")
+	require.NoError(t, err)
+	defer func() {
+		_, _ = db.Exec(`DROP TABLE gamescores_copy`)
+	}()
+	err = db.QueryRow(`SHOW CREATE TABLE gamescores_copy`).Scan(&name, &copied)
+	require.NoError(t, err)
+	require.Equal(t, strings.Replace(statement, "gamescores", "gamescores_copy", 1), copied)
+
+	// EXPLAIN shows the requests without making them
+	var operation, request string
+	var warnings sql.NullString
+	err = db.QueryRow(`EXPLAIN SELECT * FROM gamescores WHERE UserId = :user`).Scan(&operation, &request, &warnings)
+	require.NoError(t, err)
+	require.Equal(t, "Query", operation)
+	require.Contains(t, request, `":user": ":user"`)
+	require.False(t, warnings.Valid)
+}
+
+func TestTransaction(t *testing.T) {
+	fix := fixtures.Movies
+	fix.Data = nil
+	sess := fixtures.SetUp(t, fix)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+	err = db.Ping()
+	require.NoError(t, err)
+
+	prisoners := fixtures.Movie{Title: "Prisoners", Year: 2013, Info: fixtures.MovieInfo{Plot: "drama", Rating: 8}}
+	_, err = db.Exec("INSERT INTO movies VALUES (?)", prisoners)
+	require.NoError(t, err)
+	exists := func(title string, year int) bool {
+		var found string
+		err := db.QueryRow(`SELECT title FROM movies WHERE title = ? AND year = ?`, title, int64(year)).Scan(&found)
+		if err == sql.ErrNoRows {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	// Writes are applied on commit
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec(`INSERT INTO movies VALUES ('{"title": "Big", "year": 1988}')`)
+	require.NoError(t, err)
+	_, err = tx.Exec(`DELETE FROM movies WHERE title = ? AND year = ?`, prisoners.Title, int64(prisoners.Year))
+	require.NoError(t, err)
+	require.False(t, exists("Big", 1988))
+	require.True(t, exists("Prisoners", 2013))
+	require.NoError(t, tx.Commit())
+	require.True(t, exists("Big", 1988))
+	require.False(t, exists("Prisoners", 2013))
+
+	// Writes are discarded on rollback
+	tx, err = db.Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec(`INSERT INTO movies VALUES ('{"title": "Heat", "year": 1995}')`)
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+	require.False(t, exists("Heat", 1995))
+
+	// Writing an item twice fails early
+	tx, err = db.Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec(`UPDATE movies SET rating = 5 WHERE title = "Big" AND year = 1988`)
+	require.NoError(t, err)
+	_, err = tx.Exec(`DELETE FROM movies WHERE title = "Big" AND year = 1988`)
+	require.Error(t, err)
+	require.NoError(t, tx.Rollback())
+}
+
+func TestReadOnlyTransaction(t *testing.T) {
+	sess := fixtures.SetUp(t, fixtures.GameScores)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+	err = db.Ping()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	require.NoError(t, err)
+	galaxy, err := tx.QueryContext(ctx, `SELECT TopScore FROM gamescores WHERE UserId = ? AND GameTitle = ?`, "101", "Galaxy Invaders")
+	require.NoError(t, err)
+	meteor, err := tx.QueryContext(ctx, `SELECT TopScore FROM gamescores WHERE (UserId, GameTitle) IN ((?, ?), (?, ?))`,
+		"101", "Meteor Blasters", "104", "Meteor Blasters")
+	require.NoError(t, err)
+	var score int
+	require.True(t, galaxy.Next())
+	require.NoError(t, galaxy.Scan(&score))
+	require.Equal(t, 5842, score)
+	require.False(t, galaxy.Next())
+	require.True(t, meteor.Next())
+	require.NoError(t, meteor.Scan(&score))
+	require.Equal(t, 1000, score)
+	require.False(t, meteor.Next())
+	require.NoError(t, meteor.Err())
+
+	_, err = tx.QueryContext(ctx, `SELECT TopScore FROM gamescores WHERE UserId = ?`, "101")
+	require.Error(t, err)
+	_, err = tx.ExecContext(ctx, `DELETE FROM gamescores WHERE UserId = ? AND GameTitle = ?`, "101", "Galaxy Invaders")
+	require.Error(t, err)
+	require.NoError(t, tx.Commit())
+}
+
+func TestUpdate(t *testing.T) {
+	fix := fixtures.Movies
+	fix.Data = nil
+	sess := fixtures.SetUp(t, fix)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+	err = db.Ping()
+	require.NoError(t, err)
+
+	prisoners := fixtures.Movie{Title: "Prisoners", Year: 2013, Info: fixtures.MovieInfo{Plot: "drama", Rating: 8}}
+	_, err = db.Exec("INSERT INTO movies VALUES (?)", prisoners)
+	require.NoError(t, err)
+
+	v, err := db.Exec(`UPDATE movies SET info.rating = ? REMOVE info.plot WHERE title = ? AND year = ?`,
+		8.2, prisoners.Title, int64(prisoners.Year))
+	require.NoError(t, err)
+	rows, err := v.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	// Failed conditions and missing items do not affect any rows
+	v, err = db.Exec(`UPDATE movies SET info.rating = 9 WHERE title = "Prisoners" AND year = 2013 AND info.rating > 9`)
+	require.NoError(t, err)
+	rows, err = v.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), rows)
+	v, err = db.Exec(`UPDATE movies SET info.rating = 9 WHERE title = "Prisoners" AND year = 2014`)
+	require.NoError(t, err)
+	rows, err = v.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), rows)
+
+	row := db.QueryRow(`UPDATE movies SET info.plot = :plot WHERE title = :title AND year = :year RETURNING ALL_NEW`,
+		sql.Named("plot", "thriller"), sql.Named("title", prisoners.Title), sql.Named("year", int64(prisoners.Year)))
+	var movie fixtures.Movie
+	require.NoError(t, row.Scan(Document(&movie)))
+	expected := prisoners
+	expected.Info = fixtures.MovieInfo{Plot: "thriller", Rating: 8.2}
+	require.Equal(t, expected, movie)
+}
+
+func TestDelete(t *testing.T) {
+	fix := fixtures.Movies
+	fix.Data = nil
+	sess := fixtures.SetUp(t, fix)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+	err = db.Ping()
+	require.NoError(t, err)
+
+	prisoners := fixtures.Movie{Title: "Prisoners", Year: 2013, Info: fixtures.MovieInfo{Rating: 8.2}}
+	rushHour := fixtures.Movie{Title: "Rush Hour", Year: 1998, Info: fixtures.MovieInfo{Rating: 7}}
+	_, err = db.Exec("INSERT INTO movies VALUES (?)", []fixtures.Movie{prisoners, rushHour})
+	require.NoError(t, err)
+
+	// Condition does not match
+	v, err := db.Exec(`DELETE FROM movies WHERE title = ? AND year = ? AND info.rating < ?`, prisoners.Title, int64(prisoners.Year), 8.0)
+	require.NoError(t, err)
+	rows, err := v.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), rows)
+
+	v, err = db.Exec(`DELETE FROM movies WHERE title = ? AND year = ? AND info.rating > ?`, prisoners.Title, int64(prisoners.Year), 8.0)
+	require.NoError(t, err)
+	rows, err = v.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	// Item no longer exists
+	v, err = db.Exec(`DELETE FROM movies WHERE title = ? AND year = ?`, prisoners.Title, int64(prisoners.Year))
+	require.NoError(t, err)
+	rows, err = v.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), rows)
+
+	row := db.QueryRow(`DELETE FROM movies WHERE title = "Rush Hour" AND year = 1998 RETURNING ALL_OLD`)
+	var movie fixtures.Movie
+	require.NoError(t, row.Scan(Document(&movie)))
+	require.Equal(t, rushHour, movie)
+}
+
+func TestScan(t *testing.T) {
+	sess := fixtures.SetUp(t, fixtures.GameScores)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+	err = db.Ping()
+	require.NoError(t, err)
+
+	readUsers := func(rows *sql.Rows) []string {
+		var users []string
+		for rows.Next() {
+			var user, title string
+			require.NoError(t, rows.Scan(&user, &title))
+			users = append(users, user+":"+title)
+		}
+		require.NoError(t, rows.Err())
+		sort.Strings(users)
+		return users
+	}
+
+	rows, err := db.Query(`SCAN UserId, GameTitle FROM gamescores`)
+	require.NoError(t, err)
+	require.Len(t, readUsers(rows), 9)
+
+	rows, err = db.Query(`SCAN UserId, GameTitle FROM gamescores WHERE GameTitle = ? AND TopScore > ?`, "Galaxy Invaders", int64(1000))
+	require.NoError(t, err)
+	require.Equal(t, []string{"101:Galaxy Invaders", "103:Galaxy Invaders"}, readUsers(rows))
+
+	rows, err = db.Query(`SCAN UserId, GameTitle FROM gamescores LIMIT 4`)
+	require.NoError(t, err)
+	require.Len(t, readUsers(rows), 4)
+
+	rows, err = db.Query(`SCAN UserId, GameTitle FROM gamescores SEGMENTS 4`)
+	require.NoError(t, err)
+	require.Len(t, readUsers(rows), 9)
+}
+
+func TestGetItem(t *testing.T) {
+	sess := fixtures.SetUp(t, fixtures.GameScores)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+	err = db.Ping()
+	require.NoError(t, err)
+
+	var score int
+	err = db.QueryRow(`SELECT TopScore FROM gamescores WHERE UserId = ? AND GameTitle = ?`, "103", "Galaxy Invaders").Scan(&score)
+	require.NoError(t, err)
+	require.Equal(t, 2317, score)
+
+	err = db.QueryRow(`SELECT TopScore FROM gamescores WHERE UserId = ? AND GameTitle = ?`, "104", "Galaxy Invaders").Scan(&score)
+	require.Equal(t, sql.ErrNoRows, err)
+
+	rows, err := db.Query(`SELECT TopScore FROM gamescores WHERE (UserId, GameTitle) IN ((?, ?), (?, ?), (?, ?), (?, ?))`,
+		"103", "Galaxy Invaders", "101", "Starship X", "104", "Galaxy Invaders", "103", "Galaxy Invaders")
+	require.NoError(t, err)
+	var scores []int
+	for rows.Next() {
+		require.NoError(t, rows.Scan(&score))
+		scores = append(scores, score)
+	}
+	require.NoError(t, rows.Err())
+	sort.Ints(scores)
+	require.Equal(t, []int{24, 2317}, scores)
+}
+
+func TestQueryFanOut(t *testing.T) {
+	sess := fixtures.SetUp(t, fixtures.GameScores)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+	err = db.Ping()
+	require.NoError(t, err)
+
+	readGames := func(rows *sql.Rows) []string {
+		var games []string
+		for rows.Next() {
+			var user, title string
+			require.NoError(t, rows.Scan(&user, &title))
+			games = append(games, user+":"+title)
+		}
+		require.NoError(t, rows.Err())
+		return games
+	}
+
+	rows, err := db.Query(`SELECT UserId, GameTitle FROM gamescores WHERE UserId IN (?, ?, ?) AND GameTitle > "H" DESC`, "103", "102", "103")
+	require.NoError(t, err)
+	require.Equal(t, []string{"103:Starship X", "103:Meteor Blasters"}, readGames(rows))
+
+	rows, err = db.Query(`SELECT UserId, GameTitle FROM gamescores WHERE UserId = ? AND Wins > 20 OR UserId = ? AND begins_with(GameTitle, "A")`, "101", "102")
+	require.NoError(t, err)
+	require.Equal(t, []string{"101:Galaxy Invaders", "102:Alien Adventure"}, readGames(rows))
+
+	rows, err = db.Query(`SELECT UserId, GameTitle FROM gamescores WHERE UserId IN (?, ?) LIMIT 4`, "101", "102")
+	require.NoError(t, err)
+	require.Equal(t, []string{"101:Galaxy Invaders", "101:Meteor Blasters", "101:Starship X", "102:Alien Adventure"}, readGames(rows))
+}
+
+func TestCheck(t *testing.T) {
+	sess := fixtures.SetUp(t, fixtures.GameScores)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+	err = db.Ping()
+	require.NoError(t, err)
+
+	const check = `CHECK gamescores WHERE UserId = ? AND GameTitle = ? AND TopScore >= ?`
+
+	// Outside a transaction, the number of rows affected reports whether the check holds
+	result, err := db.Exec(check, "101", "Galaxy Invaders", int64(1000))
+	require.NoError(t, err)
+	affected, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), affected)
+	result, err = db.Exec(check, "101", "Starship X", int64(1000))
+	require.NoError(t, err)
+	affected, err = result.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), affected)
+
+	// A check that holds lets the transaction commit
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec(check, "101", "Galaxy Invaders", int64(1000))
+	require.NoError(t, err)
+	_, err = tx.Exec(`UPDATE gamescores SET TopScore = 6000 WHERE UserId = "102" AND GameTitle = "Galaxy Invaders"`)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	// A check that fails cancels the transaction
+	tx, err = db.Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec(check, "101", "Starship X", int64(1000))
+	require.NoError(t, err)
+	_, err = tx.Exec(`UPDATE gamescores SET TopScore = 7000 WHERE UserId = "102" AND GameTitle = "Galaxy Invaders"`)
+	require.NoError(t, err)
+	require.Error(t, tx.Commit())
+	var score int
+	err = db.QueryRow(`SELECT TopScore FROM gamescores WHERE UserId = "102" AND GameTitle = "Galaxy Invaders"`).Scan(&score)
+	require.NoError(t, err)
+	require.Equal(t, 6000, score)
+}
+
+func TestReplaceNotAtomic(t *testing.T) {
+	fix := fixtures.Movies
+	fix.Data = nil
+	sess := fixtures.SetUp(t, fix)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+	err = db.Ping()
+	require.NoError(t, err)
+
+	var movies []fixtures.Movie
+	for i := 0; i < 1000; i++ {
+		movies = append(movies, fixtures.Movie{Title: fmt.Sprintf("movie %d", i), Year: 2000})
+	}
+	result, err := db.Exec(`REPLACE INTO movies VALUES (?) NOT ATOMIC`, movies)
+	require.NoError(t, err)
+	affected, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), affected)
+
+	var count int
+	rows, err := db.Query(`SCAN title FROM movies`)
+	require.NoError(t, err)
+	for rows.Next() {
+		count++
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, 1000, count)
+}
+
+func TestScript(t *testing.T) {
+	fix := fixtures.Movies
+	fix.Data = nil
+	sess := fixtures.SetUp(t, fix)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+
+	result, err := db.Exec(`
+		INSERT INTO movies VALUES ('{"title": "Big", "year": 1988}');
+		INSERT INTO movies VALUES ('{"title": "Up", "year": 2009}');
+		UPDATE movies SET rating = 8 WHERE title = 'Up' AND year = 2009;
+	`)
+	require.NoError(t, err)
+	affected, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(3), affected)
+
+	// Each SELECT returns a result set, and sees the writes made before it
+	rows, err := db.Query(`
+		SELECT title FROM movies WHERE title = 'Big' AND year = 1988;
+		DELETE FROM movies WHERE title = 'Big' AND year = 1988;
+		SELECT title, rating FROM movies WHERE title = 'Up' AND year = 2009;
+		SELECT title FROM movies WHERE title = 'Big' AND year = 1988;
+	`)
+	require.NoError(t, err)
+	var (
+		title  string
+		rating int
+	)
+	require.True(t, rows.Next())
+	require.NoError(t, rows.Scan(&title))
+	require.Equal(t, "Big", title)
+	require.False(t, rows.Next())
+	require.True(t, rows.NextResultSet())
+	require.True(t, rows.Next())
+	require.NoError(t, rows.Scan(&title, &rating))
+	require.Equal(t, []interface{}{"Up", 8}, []interface{}{title, rating})
+	require.True(t, rows.NextResultSet())
+	require.False(t, rows.Next())
+	require.False(t, rows.NextResultSet())
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+
+	_, err = db.Exec(`
+		DELETE FROM movies WHERE title = 'Up' AND year = 2009;
+		INSERT INTO movies VALUES ('{"title": "Up", "year": 2009}');
+		INSERT INTO movies VALUES ('{"title": "Up", "year": 2009}');
+	`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "statement 3 (line 4): ")
+}
+
+func TestUpdateExpressions(t *testing.T) {
+	fix := fixtures.Movies
+	fix.Data = nil
+	sess := fixtures.SetUp(t, fix)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+
+	_, err = db.Exec(`INSERT INTO movies VALUES ('{"title": "Big", "year": 1988, "views": 1}')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		UPDATE movies
+		SET views = views + :n, genres = list_append(if_not_exists(genres, :empty), :genres)
+		ADD visitors :visitors
+		WHERE title = :title AND year = :year`,
+		sql.Named("n", int64(2)),
+		sql.Named("empty", &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}),
+		sql.Named("genres", &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{{S: aws.String("comedy")}}}),
+		sql.Named("visitors", &dynamodb.AttributeValue{SS: aws.StringSlice([]string{"alice", "bob"})}),
+		sql.Named("title", "Big"),
+		sql.Named("year", int64(1988)),
+	)
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE movies DELETE visitors ? WHERE title = 'Big' AND year = 1988`,
+		&dynamodb.AttributeValue{SS: aws.StringSlice([]string{"bob"})})
+	require.NoError(t, err)
+
+	var movie struct {
+		Views    int      `dynamodbav:"views"`
+		Genres   []string `dynamodbav:"genres"`
+		Visitors []string `dynamodbav:"visitors,stringset"`
+	}
+	err = db.QueryRow(`SELECT * FROM movies WHERE title = 'Big' AND year = 1988`).Scan(Document(&movie))
+	require.NoError(t, err)
+	require.Equal(t, 3, movie.Views)
+	require.Equal(t, []string{"comedy"}, movie.Genres)
+	require.Equal(t, []string{"alice"}, movie.Visitors)
+}
+
+func TestReplaceWhere(t *testing.T) {
+	fix := fixtures.Movies
+	fix.Data = nil
+	sess := fixtures.SetUp(t, fix)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+
+	_, err = db.Exec(`INSERT INTO movies VALUES ('{"title": "Big", "year": 1988, "version": 1}')`)
+	require.NoError(t, err)
+	replace := `REPLACE INTO movies VALUES (:doc) WHERE version = :expected`
+	_, err = db.Exec(replace,
+		sql.Named("doc", `{"title": "Big", "year": 1988, "version": 2}`),
+		sql.Named("expected", int64(1)))
+	require.NoError(t, err)
+	_, err = db.Exec(replace,
+		sql.Named("doc", `{"title": "Big", "year": 1988, "version": 2}`),
+		sql.Named("expected", int64(1)))
+	require.True(t, errors.Is(err, ErrConditionFailed), "%v", err)
+}
+
+func TestInsertOnConflict(t *testing.T) {
+	fix := fixtures.Movies
+	fix.Data = nil
+	sess := fixtures.SetUp(t, fix)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+
+	_, err = db.Exec(`INSERT INTO movies VALUES ('{"title": "Big", "year": 1988, "plot": "A boy wakes up big"}')`)
+	require.NoError(t, err)
+
+	// Existing keys are skipped
+	result, err := db.Exec(`INSERT INTO movies VALUES ({"title": "Big", "year": 1988}), ({"title": "Up", "year": 2009}) ON CONFLICT DO NOTHING`)
+	require.NoError(t, err)
+	count, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	// Existing items are merged with the inserted attributes
+	upsert := `INSERT INTO movies VALUES (:movie) ON CONFLICT DO UPDATE SET views = if_not_exists(views, 0) + 1`
+	for i := 0; i < 2; i++ {
+		_, err = db.Exec(upsert, sql.Named("movie", `{"title": "Big", "year": 1988, "rating": 7}`))
+		require.NoError(t, err)
+	}
+	var plot string
+	var rating, views int
+	err = db.QueryRow(`SELECT plot, rating, views FROM movies WHERE title = "Big" AND year = 1988`).Scan(&plot, &rating, &views)
+	require.NoError(t, err)
+	require.Equal(t, "A boy wakes up big", plot)
+	require.Equal(t, 7, rating)
+	require.Equal(t, 2, views)
+
+	// New items are inserted as they are, without the SET actions
+	_, err = db.Exec(upsert, sql.Named("movie", `{"title": "Heat", "year": 1995, "views": 5}`))
+	require.NoError(t, err)
+	err = db.QueryRow(`SELECT views FROM movies WHERE title = "Heat" AND year = 1995`).Scan(&views)
+	require.NoError(t, err)
+	require.Equal(t, 5, views)
+}
+
+func TestInsertColumns(t *testing.T) {
+	fix := fixtures.Movies
+	fix.Data = nil
+	sess := fixtures.SetUp(t, fix)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+
+	result, err := db.Exec(`INSERT INTO movies (title, year, actors) VALUES (?, ?, ?), (?, ?, ?)`,
+		"Big", 1988, []string{"Tom Hanks"},
+		"Up", 2009, []string{"Ed Asner", "Jordan Nagai"})
+	require.NoError(t, err)
+	count, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	_, err = db.Exec(`INSERT INTO movies (title, year, plot) VALUES (:title, :year, 'Fire')`,
+		sql.Named("title", "Heat"), sql.Named("year", 1995))
+	require.NoError(t, err)
+
+	var plot string
+	err = db.QueryRow(`SELECT plot FROM movies WHERE title = "Heat" AND year = 1995`).Scan(&plot)
+	require.NoError(t, err)
+	require.Equal(t, "Fire", plot)
+}
+
+func TestInsertTemplate(t *testing.T) {
+	fix := fixtures.Movies
+	fix.Data = nil
+	sess := fixtures.SetUp(t, fix)
+
+	driver, err := New(Config{Session: sess}).OpenConnector("")
+	require.NoError(t, err)
+	db := sql.OpenDB(driver)
+
+	_, err = db.Exec(`INSERT INTO movies VALUES ({"title": :title, "year": :year, "info": {"director": :director, "genres": [:g1, :g2]}})`,
+		sql.Named("title", "Big"), sql.Named("year", 1988), sql.Named("director", "Penny Marshall"),
+		sql.Named("g1", "comedy"), sql.Named("g2", "fantasy"))
+	require.NoError(t, err)
+
+	var director string
+	err = db.QueryRow(`SELECT info.director FROM movies WHERE title = "Big" AND year = 1988`).Scan(&director)
+	require.NoError(t, err)
+	require.Equal(t, "Penny Marshall", director)
+}
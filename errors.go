@@ -0,0 +1,7 @@
+package dynamosql
+
+import "github.com/mightyguava/dynamosql/querybuilder"
+
+// ErrConditionFailed is returned by REPLACE ... WHERE when the condition does not hold for the existing item. Test
+// for it with errors.Is, since it wraps the error from DynamoDB.
+var ErrConditionFailed = querybuilder.ErrConditionFailed
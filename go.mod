@@ -5,9 +5,8 @@ go 1.14
 require (
 	github.com/alecthomas/participle v1.0.0-alpha3
 	github.com/alecthomas/repr v0.0.0-20201103221029-55c485bd663f
-	github.com/aws/aws-sdk-go v1.28.7
+	github.com/aws/aws-sdk-go v1.44.300
 	github.com/sebdah/goldie/v2 v2.5.1
 	github.com/stretchr/testify v1.4.0
-	golang.org/x/net v0.0.0-20201031054903-ff519b6c9102 // indirect
-	golang.org/x/sync v0.0.0-20201020160332-67f06af15bc9
+	golang.org/x/sync v0.0.0-20220722155255-886fb9371eb4
 )
@@ -0,0 +1,37 @@
+// nolint: govet
+package parser
+
+type AlterTable struct {
+	Table   string              `"ALTER" "TABLE" @( Ident ( "." Ident )* | QuotedIdent )`
+	Actions []*AlterTableAction `@@ ( "," @@ )*`
+	Wait    bool                `@"WAIT"?`
+}
+
+func (a *AlterTable) children() (children []Node) {
+	for _, action := range a.Actions {
+		children = append(children, action)
+	}
+	return
+}
+
+type AlterTableAction struct {
+	AddIndex              *GlobalSecondaryIndex  `  "ADD" ( @@`
+	AddAttr               *TableAttr             `        | @@ )`
+	DropIndex             *string                `| "DROP" "INDEX" @(Ident | QuotedIdent)`
+	ProvisionedThroughput *ProvisionedThroughput `| "SET" @@`
+	AlterIndex            *AlterIndex            `| @@`
+}
+
+func (a *AlterTableAction) children() (children []Node) {
+	return []Node{a.AddIndex, a.AddAttr, a.ProvisionedThroughput, a.AlterIndex}
+}
+
+// AlterIndex changes the settings of a global secondary index.
+type AlterIndex struct {
+	Name                  string                 `"ALTER" "INDEX" @(Ident | QuotedIdent)`
+	ProvisionedThroughput *ProvisionedThroughput `"SET" @@`
+}
+
+func (a *AlterIndex) children() (children []Node) {
+	return []Node{a.ProvisionedThroughput}
+}
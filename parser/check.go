@@ -0,0 +1,11 @@
+// nolint: govet
+package parser
+
+type Check struct {
+	Table string         `"CHECK" @( Ident ( "." Ident )* | QuotedIdent )`
+	Where *AndExpression `"WHERE" @@`
+}
+
+func (c *Check) children() (children []Node) {
+	return []Node{c.Where}
+}
@@ -1,10 +1,19 @@
 // nolint: govet
 package parser
 
+import (
+	"strconv"
+	"strings"
+)
+
 type CreateTable struct {
-	Table                 string                 `"CREATE" "TABLE" @( Ident ( "." Ident )* | QuotedIdent ) "("`
+	IfNotExists           bool                   `"CREATE" "TABLE" ( @"IF" "NOT" "EXISTS" )?`
+	Table                 string                 `@( Ident ( "." Ident )* | QuotedIdent ) "("`
 	Entries               []*CreateTableEntry    `@@ ("," @@)* ")"`
-	ProvisionedThroughput *ProvisionedThroughput `@@`
+	BillingMode           *string                `( "BILLING" "MODE" @( "PAY_PER_REQUEST" | "PROVISIONED" ) )?`
+	ProvisionedThroughput *ProvisionedThroughput `@@?`
+	Options               []*TableOption         `( "WITH" "(" @@ ( "," @@ )* ")" )?`
+	Wait                  bool                   `@"WAIT"?`
 }
 
 func (c *CreateTable) children() (children []Node) {
@@ -12,9 +21,77 @@ func (c *CreateTable) children() (children []Node) {
 		children = append(children, entry)
 	}
 	children = append(children, c.ProvisionedThroughput)
+	for _, option := range c.Options {
+		children = append(children, option)
+	}
 	return
 }
 
+// String formats the statement so that it parses back to an equivalent CreateTable, with each entry on its own line.
+func (c *CreateTable) String() string {
+	buf := &strings.Builder{}
+	buf.WriteString("CREATE TABLE ")
+	if c.IfNotExists {
+		buf.WriteString("IF NOT EXISTS ")
+	}
+	buf.WriteString(quoteIdent(c.Table))
+	buf.WriteString(" (\n")
+	for i, entry := range c.Entries {
+		buf.WriteString("  ")
+		buf.WriteString(entry.String())
+		if i < len(c.Entries)-1 {
+			buf.WriteRune(',')
+		}
+		buf.WriteRune('\n')
+	}
+	buf.WriteRune(')')
+	if c.BillingMode != nil {
+		buf.WriteString("\nBILLING MODE ")
+		buf.WriteString(strings.ToUpper(*c.BillingMode))
+	}
+	if c.ProvisionedThroughput != nil {
+		buf.WriteRune('\n')
+		buf.WriteString(c.ProvisionedThroughput.String())
+	}
+	if len(c.Options) > 0 {
+		buf.WriteString("\nWITH (\n")
+		for i, option := range c.Options {
+			buf.WriteString("  ")
+			buf.WriteString(option.String())
+			if i < len(c.Options)-1 {
+				buf.WriteRune(',')
+			}
+			buf.WriteRune('\n')
+		}
+		buf.WriteRune(')')
+	}
+	if c.Wait {
+		buf.WriteString("\nWAIT")
+	}
+	return buf.String()
+}
+
+// TableOption is a NAME = value option of a table, such as STREAM = NEW_IMAGE or TAGS = {team: 'payments'}.
+type TableOption struct {
+	Name  string     `@Ident "="`
+	Ident *string    `( @( Ident | Keyword )`
+	Value *JSONValue `| @@ )`
+}
+
+func (o *TableOption) children() []Node { return []Node{o.Value} }
+
+func (o *TableOption) String() string {
+	switch {
+	case o.Ident != nil:
+		return strings.ToUpper(o.Name) + " = " + *o.Ident
+	case o.Value.Str != nil:
+		return strings.ToUpper(o.Name) + " = " + quoteString(*o.Value.Str)
+	case o.Value.Boolean != nil:
+		return strings.ToUpper(o.Name) + " = " + strings.ToUpper(o.Value.String())
+	}
+	return strings.ToUpper(o.Name) + " = " + o.Value.String()
+}
+
 type CreateTableEntry struct {
 	GlobalSecondaryIndex *GlobalSecondaryIndex `  @@`
 	LocalSecondaryIndex  *LocalSecondaryIndex  `| @@`
@@ -25,6 +102,17 @@ func (c *CreateTableEntry) children() (children []Node) {
 	return []Node{c.GlobalSecondaryIndex, c.LocalSecondaryIndex, c.Attr}
 }
 
+func (c *CreateTableEntry) String() string {
+	switch {
+	case c.GlobalSecondaryIndex != nil:
+		return c.GlobalSecondaryIndex.String()
+	case c.LocalSecondaryIndex != nil:
+		return c.LocalSecondaryIndex.String()
+	default:
+		return c.Attr.String()
+	}
+}
+
 type ProvisionedThroughput struct {
 	ReadCapacityUnits  int64 `"PROVISIONED" "THROUGHPUT" "READ" @Number`
 	WriteCapacityUnits int64 `"WRITE" @Number`
@@ -32,26 +120,57 @@ type ProvisionedThroughput struct {
 
 func (p *ProvisionedThroughput) children() []Node { return nil }
 
+func (p *ProvisionedThroughput) String() string {
+	return "PROVISIONED THROUGHPUT READ " + strconv.FormatInt(p.ReadCapacityUnits, 10) +
+		" WRITE " + strconv.FormatInt(p.WriteCapacityUnits, 10)
+}
+
 type GlobalSecondaryIndex struct {
 	Name                  string                 `"GLOBAL" "SECONDARY"? "INDEX" @(Ident | QuotedIdent)`
 	PartitionKey          string                 `"HASH" "(" @(Ident | QuotedIdent) ")"`
 	SortKey               string                 `("RANGE" "(" @(Ident | QuotedIdent) ")")?`
 	Projection            *Projection            `"PROJECTION" @@`
-	ProvisionedThroughput *ProvisionedThroughput `@@`
+	ProvisionedThroughput *ProvisionedThroughput `@@?`
 }
 
 func (c *GlobalSecondaryIndex) children() (children []Node) {
 	return []Node{c.Projection, c.ProvisionedThroughput}
 }
 
+func (c *GlobalSecondaryIndex) String() string {
+	out := "GLOBAL INDEX " + quoteIdent(c.Name) + " HASH(" + quoteIdent(c.PartitionKey) + ")"
+	if c.SortKey != "" {
+		out += " RANGE(" + quoteIdent(c.SortKey) + ")"
+	}
+	out += " PROJECTION " + c.Projection.String()
+	if c.ProvisionedThroughput != nil {
+		out += " " + c.ProvisionedThroughput.String()
+	}
+	return out
+}
+
 type Projection struct {
 	KeysOnly bool     `  @"KEYS_ONLY"`
 	All      bool     `| @"ALL"`
-	Include  []string `| "INCLUDE" (@(Ident | QuotedIdent) ("," (@(Ident | QuotedIdent)))*)`
+	Include  []string `| "INCLUDE" ( "(" @(Ident | QuotedIdent) ("," @(Ident | QuotedIdent))* ")" | @(Ident | QuotedIdent) ("," (@(Ident | QuotedIdent)))* )`
 }
 
 func (p *Projection) children() []Node { return nil }
 
+func (p *Projection) String() string {
+	switch {
+	case p.KeysOnly:
+		return "KEYS_ONLY"
+	case p.All:
+		return "ALL"
+	}
+	include := make([]string, 0, len(p.Include))
+	for _, attr := range p.Include {
+		include = append(include, quoteIdent(attr))
+	}
+	return "INCLUDE (" + strings.Join(include, ", ") + ")"
+}
+
 type LocalSecondaryIndex struct {
 	Name       string      `"LOCAL" "SECONDARY"? "INDEX" @(Ident | QuotedIdent)`
 	SortKey    string      `"RANGE" "(" @( Ident ( "." Ident )* | QuotedIdent ) ")"`
@@ -62,6 +181,10 @@ func (c *LocalSecondaryIndex) children() (children []Node) {
 	return []Node{c.Projection}
 }
 
+func (c *LocalSecondaryIndex) String() string {
+	return "LOCAL INDEX " + quoteIdent(c.Name) + " RANGE(" + quoteIdent(c.SortKey) + ") PROJECTION " + c.Projection.String()
+}
+
 type TableAttr struct {
 	Name string `@(Ident | QuotedIdent)`
 	Type string `@Type`
@@ -69,3 +192,11 @@ type TableAttr struct {
 }
 
 func (c *TableAttr) children() []Node { return nil }
+
+func (c *TableAttr) String() string {
+	out := quoteIdent(c.Name) + " " + strings.ToUpper(c.Type)
+	if c.Key != "" {
+		out += " " + strings.ToUpper(c.Key) + " KEY"
+	}
+	return out
+}
@@ -0,0 +1,12 @@
+// nolint: govet
+package parser
+
+type Delete struct {
+	Table     string         `"DELETE" "FROM" @( Ident ( "." Ident )* | QuotedIdent )`
+	Where     *AndExpression `"WHERE" @@`
+	Returning *string        `( "RETURNING" @( "NONE" | "ALL_OLD" ) )?`
+}
+
+func (d *Delete) children() (children []Node) {
+	return []Node{d.Where}
+}
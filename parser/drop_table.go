@@ -2,7 +2,9 @@
 package parser
 
 type DropTable struct {
-	Table string `"DROP" "TABLE" @( Ident ( "." Ident )* | QuotedIdent )`
+	IfExists bool   `"DROP" "TABLE" ( @"IF" "EXISTS" )?`
+	Table    string `@( Ident ( "." Ident )* | QuotedIdent )`
+	Wait     bool   `@"WAIT"?`
 }
 
 func (d *DropTable) children() []Node { return nil }
@@ -0,0 +1,9 @@
+// nolint: govet
+package parser
+
+// Explain describes the requests a statement would make, without making them.
+type Explain struct {
+	Statement *AST `"EXPLAIN" @@`
+}
+
+func (e *Explain) children() []Node { return []Node{e.Statement} }
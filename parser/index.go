@@ -0,0 +1,33 @@
+// nolint: govet
+package parser
+
+// CreateIndex adds a global secondary index to an existing table.
+type CreateIndex struct {
+	Name                  string                 `"CREATE" "GLOBAL" "SECONDARY"? "INDEX" @(Ident | QuotedIdent)`
+	Table                 string                 `"ON" @( Ident ( "." Ident )* | QuotedIdent )`
+	PartitionKey          *IndexKey              `"HASH" "(" @@ ")"`
+	SortKey               *IndexKey              `( "RANGE" "(" @@ ")" )?`
+	Projection            *Projection            `"PROJECTION" @@`
+	ProvisionedThroughput *ProvisionedThroughput `@@?`
+	Wait                  bool                   `@"WAIT"?`
+}
+
+func (c *CreateIndex) children() (children []Node) {
+	return []Node{c.PartitionKey, c.SortKey, c.Projection, c.ProvisionedThroughput}
+}
+
+// IndexKey is a key attribute of an index. The type is needed if the table does not already define the attribute.
+type IndexKey struct {
+	Name string `@(Ident | QuotedIdent)`
+	Type string `@Type?`
+}
+
+func (k *IndexKey) children() []Node { return nil }
+
+type DropIndex struct {
+	Name  string `"DROP" "INDEX" @(Ident | QuotedIdent)`
+	Table string `"ON" @( Ident ( "." Ident )* | QuotedIdent )`
+	Wait  bool   `@"WAIT"?`
+}
+
+func (d *DropIndex) children() []Node { return nil }
@@ -2,24 +2,59 @@
 package parser
 
 type InsertOrReplace struct {
-	Replace   bool              `("INSERT" | @"REPLACE")`
-	Into      string            `"INTO" @( Ident ( "." Ident )* | QuotedIdent )`
-	Values    []*InsertTerminal `"VALUES" "(" @@ ")" ( "," "(" @@ ")" )* `
-	Returning *string           `( "RETURNING" @( "NONE" | "ALL_OLD" ) )?`
+	Replace    bool              `("INSERT" | @"REPLACE")`
+	Into       string            `"INTO" @( Ident ( "." Ident )* | QuotedIdent )`
+	Columns    []string          `( "(" @( Ident | QuotedIdent ) ( "," @( Ident | QuotedIdent ) )* ")" "VALUES"`
+	Rows       []*InsertRow      `  @@ ( "," @@ )*`
+	Values     []*InsertTerminal `| "VALUES" "(" @@ ")" ( "," "(" @@ ")" )* )`
+	OnConflict *OnConflict       `@@?`
+	Where      *AndExpression    `( "WHERE" @@ )?`
+	NotAtomic  bool              `( @"NOT" "ATOMIC" )?`
+	Returning  *string           `( "RETURNING" @( "NONE" | "ALL_OLD" ) )?`
 }
 
 func (i *InsertOrReplace) children() (children []Node) {
+	for _, row := range i.Rows {
+		children = append(children, row)
+	}
 	for _, value := range i.Values {
 		children = append(children, value)
 	}
+	children = append(children, i.OnConflict, i.Where)
+	return
+}
+
+// OnConflict is what an INSERT does with items whose key already exists: skip them, or update them with SET.
+type OnConflict struct {
+	DoNothing bool             `"ON" "CONFLICT" "DO" ( @"NOTHING"`
+	Update    []*SetExpression `| "UPDATE" "SET" @@ ( "," @@ )* )`
+}
+
+func (o *OnConflict) children() (children []Node) {
+	for _, set := range o.Update {
+		children = append(children, set)
+	}
+	return
+}
+
+// InsertRow is a row of values for the columns of an INSERT, in the same order as the columns.
+type InsertRow struct {
+	Values []*InsertTerminal `"(" @@ ( "," @@ )* ")"`
+}
+
+func (r *InsertRow) children() (children []Node) {
+	for _, value := range r.Values {
+		children = append(children, value)
+	}
 	return
 }
 
 type InsertTerminal struct {
 	Value
 	Object *JSONObject `| @@`
+	Array  *JSONArray  `| @@`
 }
 
 func (i *InsertTerminal) children() (children []Node) {
-	return append(i.Value.children(), i.Object)
+	return append(i.Value.children(), i.Object, i.Array)
 }
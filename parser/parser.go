@@ -2,6 +2,8 @@
 package parser
 
 import (
+	"io"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -11,8 +13,11 @@ import (
 )
 
 var (
-	Lexer = stateful.MustSimple([]stateful.Rule{
+	Lexer = &hintLexerDef{stateful.MustSimple([]stateful.Rule{
 		{"Whitespace", `\s+`, nil},
+		// Hints look like block comments, but are kept for the parser.
+		{"Hint", `/\*\+([^*]|\*+[^*/])*\*+/`, nil},
+		{"Comment", `--[^\n]*|/\*([^*]|\*+[^*/])*\*+/`, nil},
 		{"Bool", `(?i)\b(TRUE|FALSE)\b`, nil},
 		{"Type", `(?i)\b(STRING|NUMBER|BINARY)\b`, nil},
 		{"Null", `(?i)\bNULL\b`, nil},
@@ -23,16 +28,18 @@ var (
 		{"String", `'[^']*'|"[^"]*"`, nil},
 		{"Operator", `<>|!=|<=|>=|[-+*/%:?,.()=<>\[\]{};]`, nil},
 	},
-	)
-	parser = participle.MustBuild(
-		&AST{},
+	)}
+	parser       = participle.MustBuild(&statement{}, options...)
+	scriptParser = participle.MustBuild(&Script{}, options...)
+	options      = []participle.Option{
 		participle.Lexer(Lexer),
 		participle.Unquote("String"),
 		UnquoteIdent(),
-		participle.CaseInsensitive("Keyword", "Bool", "Type", "Null"),
-		participle.UseLookahead(2),
-		participle.Elide("Whitespace"),
-	)
+		// Ident is case insensitive so that contextual keywords match in any case.
+		participle.CaseInsensitive("Keyword", "Ident", "Bool", "Type", "Null"),
+		participle.UseLookahead(3),
+		participle.Elide("Whitespace", "Comment"),
+	}
 )
 
 var keywords = []string{
@@ -42,14 +49,42 @@ var keywords = []string{
 	"SECONDARY", "RETURNING", "NONE", "ALL_OLD", "UPDATED_OLD", "ALL_NEW", "UPDATED_NEW", "DELETE", "CHECK",
 }
 
+// contextualKeywords are lexed as an Ident, and are only keywords where the grammar expects them, so attributes may
+// still have these names.
+var contextualKeywords = []string{
+	"UPDATE", "SET", "REMOVE",
+	"SCAN", "SEGMENTS",
+	"ATOMIC",
+	"ALTER", "ADD",
+	"BILLING", "MODE", "PAY_PER_REQUEST",
+	"WITH",
+	"IF", "EXISTS", "WAIT",
+	"ON",
+	"SHOW", "TABLES", "INDEXES", "LIKE", "DESCRIBE",
+	"EXPLAIN",
+	"CONFLICT", "DO", "NOTHING",
+}
+
 func keywordsRe() string {
 	return `(?i)\b(` + strings.Join(keywords, "|") + `)\b`
 }
 
+// statement is a single statement, with an optional trailing semicolon.
+type statement struct {
+	AST *AST `@@ ";"?`
+}
+
 func Parse(s string) (*AST, error) {
-	var ast AST
-	err := parser.ParseString("", s, &ast)
-	return &ast, err
+	stmt := statement{AST: &AST{}}
+	err := parser.ParseString("", s, &stmt)
+	return stmt.AST, err
+}
+
+// ParseScript parses a script of one or more statements separated by semicolons.
+func ParseScript(s string) (*Script, error) {
+	var script Script
+	err := scriptParser.ParseString("", s, &script)
+	return &script, err
 }
 
 // EBNF grammar for the SQL parser.
@@ -65,6 +100,71 @@ func UnquoteIdent() participle.Option {
 	}, "QuotedIdent")
 }
 
+// hintLexerDef lexes a hint as a comment, so that it is elided, unless it follows the SELECT or SCAN that starts a
+// statement.
+type hintLexerDef struct {
+	lexer.Definition
+}
+
+func (d *hintLexerDef) Lex(filename string, r io.Reader) (lexer.Lexer, error) {
+	l, err := d.Definition.Lex(filename, r)
+	if err != nil {
+		return nil, err
+	}
+	symbols := d.Symbols()
+	return &hintLexer{
+		Lexer:   l,
+		hint:    symbols["Hint"],
+		comment: symbols["Comment"],
+		elided:  map[rune]bool{symbols["Whitespace"]: true, symbols["Comment"]: true},
+	}, nil
+}
+
+type hintLexer struct {
+	lexer.Lexer
+	hint, comment rune
+	elided        map[rune]bool
+	// prev and start are the last token that is not elided, and whether it starts a statement.
+	prev  lexer.Token
+	start bool
+}
+
+func (l *hintLexer) Next() (lexer.Token, error) {
+	t, err := l.Lexer.Next()
+	if err != nil || l.elided[t.Type] {
+		return t, err
+	}
+	if t.Type == l.hint && !(strings.EqualFold(l.prev.Value, "SELECT") || l.start && strings.EqualFold(l.prev.Value, "SCAN")) {
+		t.Type = l.comment
+		return t, nil
+	}
+	l.start = l.prev.Value == "" || l.prev.Value == ";"
+	l.prev = t
+	return t, nil
+}
+
+var plainIdent = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// quoteIdent quotes an identifier with backticks, unless it would lex as an Ident.
+func quoteIdent(name string) string {
+	if plainIdent.MatchString(name) && !reservedToken.MatchString(name) {
+		return name
+	}
+	return "`" + name + "`"
+}
+
+// reservedToken matches the names that are quoted, which includes contextual keywords to keep output unambiguous.
+var reservedToken = regexp.MustCompile(`(?i)^(TRUE|FALSE|STRING|NUMBER|BINARY|NULL|` +
+	strings.Join(keywords, "|") + "|" + strings.Join(contextualKeywords, "|") + `)$`)
+
+// quoteString quotes a string with single quotes. Quotes within the string are escaped, since the lexer does not
+// allow a String to contain its own quote character.
+func quoteString(s string) string {
+	quoted := strconv.Quote(s)
+	quoted = strings.ReplaceAll(quoted[1:len(quoted)-1], `\"`, `"`)
+	return "'" + strings.ReplaceAll(quoted, "'", `\x27`) + "'"
+}
+
 type Boolean bool
 
 func (b *Boolean) Capture(values []string) error {
@@ -87,12 +187,23 @@ type Node interface {
 type AST struct {
 	Select      *Select          `(  @@`
 	Insert      *InsertOrReplace ` | @@`
+	Update      *Update          ` | @@`
+	Delete      *Delete          ` | @@`
+	Check       *Check           ` | @@`
 	CreateTable *CreateTable     ` | @@`
-	DropTable   *DropTable       ` | @@ ) ";"?`
+	AlterTable  *AlterTable      ` | @@`
+	DropTable   *DropTable       ` | @@`
+	CreateIndex *CreateIndex     ` | @@`
+	DropIndex   *DropIndex       ` | @@`
+	ShowTables  *ShowTables      ` | @@`
+	ShowIndexes *ShowIndexes     ` | @@`
+	ShowCreate  *ShowCreateTable ` | @@`
+	Describe    *Describe        ` | @@`
+	Explain     *Explain         ` | @@ )`
 }
 
 func (a *AST) children() (children []Node) {
-	return []Node{a.Select, a.Insert, a.CreateTable, a.DropTable}
+	return []Node{a.Select, a.Insert, a.Update, a.Delete, a.Check, a.CreateTable, a.AlterTable, a.DropTable, a.CreateIndex, a.DropIndex, a.ShowTables, a.ShowIndexes, a.ShowCreate, a.Describe, a.Explain}
 }
 
 type JSONObjectEntry struct {
@@ -142,16 +253,35 @@ func (j *JSONArray) String() string {
 	return "[" + strings.Join(out, ",") + "]"
 }
 
+// JSONValue is a value in a JSON document. In INSERT, it may also be a placeholder, which is bound when the
+// statement is executed.
 type JSONValue struct {
 	Scalar
-	Object *JSONObject `| @@`
-	Array  *JSONArray  `| @@`
+	PlaceHolder           *string     `| @":" @Ident`
+	PositionalPlaceholder bool        `| @"?"`
+	Object                *JSONObject `| @@`
+	Array                 *JSONArray  `| @@`
 }
 
 func (j *JSONValue) children() (children []Node) {
 	return append(j.Scalar.children(), j.Object, j.Array)
 }
 
+func (j *JSONValue) String() string {
+	switch {
+	case j.Object != nil:
+		return j.Object.String()
+	case j.Array != nil:
+		return j.Array.String()
+	case j.PlaceHolder != nil:
+		return *j.PlaceHolder
+	case j.PositionalPlaceholder:
+		return "?"
+	default:
+		return j.Scalar.String()
+	}
+}
+
 type Scalar struct {
 	Number  *float64 `  @Number`
 	Str     *string  `| @String`
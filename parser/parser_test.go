@@ -176,3 +176,109 @@ VALUES ({title:"hello",year:2009}),
 		})
 	}
 }
+
+func TestCreateTableString(t *testing.T) {
+	queries := []string{
+		`CREATE TABLE movies (title STRING HASH KEY, year NUMBER RANGE KEY, GLOBAL SECONDARY INDEX year_title HASH(year) RANGE(title) PROJECTION INCLUDE (director, actors) PROVISIONED THROUGHPUT READ 1 WRITE 2, LOCAL INDEX title_rating RANGE(rating) PROJECTION KEYS_ONLY, rating NUMBER) PROVISIONED THROUGHPUT READ 3 WRITE 4`,
+		`CREATE TABLE IF NOT EXISTS events (id STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WITH (STREAM = NEW_AND_OLD_IMAGES, TTL = 'expires at', SSE_KMS_KEY = "it's \\ \x22quoted\x22", DELETION_PROTECTION = TRUE, TAGS = {team: 'payments'}) WAIT`,
+		"CREATE TABLE `my-table.v2` (`range` BINARY HASH KEY, `user-id` STRING, GLOBAL INDEX `user-id` HASH(`user-id`) PROJECTION ALL) BILLING MODE PAY_PER_REQUEST",
+	}
+	for _, query := range queries {
+		ast, err := Parse(query)
+		require.NoError(t, err, query)
+		formatted := ast.CreateTable.String()
+		t.Log(formatted)
+		reparsed, err := Parse(formatted)
+		require.NoError(t, err, formatted)
+		require.Equal(t, ast.CreateTable, reparsed.CreateTable)
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	script, err := ParseScript(`
+CREATE TABLE movies (title STRING HASH KEY);;
+EXPLAIN SELECT * FROM movies; DROP TABLE movies
+`)
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 3)
+	var lines []int
+	for _, stmt := range script.Statements {
+		lines = append(lines, stmt.Pos.Line)
+	}
+	require.Equal(t, []int{2, 3, 3}, lines)
+	require.NotNil(t, script.Statements[0].AST.CreateTable)
+	require.NotNil(t, script.Statements[1].AST.Explain)
+	require.NotNil(t, script.Statements[2].AST.DropTable)
+
+	_, err = ParseScript("SELECT * FROM movies SELECT * FROM movies")
+	require.EqualError(t, err, `1:22: unexpected token "SELECT"`)
+}
+
+func TestComments(t *testing.T) {
+	script, err := ParseScript(`
+-- Movies; with a semicolon
+CREATE TABLE movies (
+  title STRING HASH KEY -- the title
+);
+/* Not run:
+DROP TABLE movies;
+*/
+SELECT /*+ hint */ title FROM movies`)
+	require.NoError(t, err)
+	require.Len(t, script.Statements, 2)
+	require.Equal(t, 3, script.Statements[0].Pos.Line)
+	require.Equal(t, 9, script.Statements[1].Pos.Line)
+	require.Equal(t, "/*+ hint */", *script.Statements[1].AST.Select.Hint)
+
+	// Hints are ignored like comments wherever they are not used.
+	ast, err := Parse("DROP TABLE /*+ hint */ movies")
+	require.NoError(t, err)
+	require.Equal(t, "movies", ast.DropTable.Table)
+	ast, err = Parse("SELECT /*+ first */ title /*+ second */ FROM movies WHERE title = /*+ third */ :title")
+	require.NoError(t, err)
+	require.Equal(t, "/*+ first */", *ast.Select.Hint)
+	ast, err = Parse("SCAN /*+ hint */ * FROM movies")
+	require.NoError(t, err)
+	require.Equal(t, "/*+ hint */", *ast.Select.Hint)
+	ast, err = Parse("SELECT scan /*+ hint */ FROM movies")
+	require.NoError(t, err)
+	require.Nil(t, ast.Select.Hint)
+}
+
+func TestContextualKeywords(t *testing.T) {
+	// Attributes named after contextual keywords parse as they did before the keywords were added.
+	for _, query := range []string{
+		`SELECT update, set, remove FROM movies WHERE title = :title AND set = 1`,
+		`UPDATE movies SET set = 1, update = :update REMOVE remove WHERE title = :title AND year = 1988`,
+		`update movies set rating = 5 where title = :title and year = 1988`,
+		`SELECT scan, segments FROM movies WHERE title = :title AND segments = 1`,
+		`scan * from movies segments 4`,
+		`SELECT atomic FROM movies WHERE title = :title AND atomic = true`,
+		`REPLACE INTO movies VALUES (?) not atomic`,
+		`SELECT alter, add FROM movies WHERE title = :title AND add = 1`,
+		`ALTER TABLE movies ADD add STRING, alter index year_title set provisioned throughput read 1 write 1`,
+		`SELECT mode, status FROM t WHERE id = :id`,
+		`SELECT billing, pay_per_request FROM t WHERE id = :id AND mode = "fast"`,
+		`CREATE TABLE t (id STRING HASH KEY, mode STRING) billing mode pay_per_request`,
+		`SELECT with FROM t WHERE id = :id AND with = 1`,
+		`CREATE TABLE t (id STRING HASH KEY) BILLING MODE PAY_PER_REQUEST with (TTL = expires_at)`,
+		`SELECT if, exists, wait FROM t WHERE id = :id AND wait = 1 AND exists = 1`,
+		`create table if not exists t (id STRING HASH KEY) BILLING MODE PAY_PER_REQUEST wait`,
+		`DROP TABLE if`,
+		`DROP TABLE IF EXISTS wait WAIT`,
+		`SELECT on FROM t WHERE id = :id AND on = 1`,
+		`DROP INDEX on on on`,
+		`SELECT show, tables, indexes, like, describe FROM t WHERE id = :id AND tables = 1`,
+		`show tables like "t%"`,
+		`SHOW INDEXES FROM tables`,
+		`describe show`,
+		`SELECT explain FROM t WHERE id = :id AND explain = 1`,
+		`explain select * from t where id = :id`,
+		`SELECT conflict, do, nothing FROM t WHERE id = :id AND do = 1`,
+		`INSERT INTO t VALUES (?) on conflict do nothing`,
+		`INSERT INTO t VALUES (?) ON CONFLICT DO UPDATE SET do = do + 1`,
+	} {
+		_, err := Parse(query)
+		require.NoError(t, err, query)
+	}
+}
@@ -0,0 +1,25 @@
+// nolint: govet
+package parser
+
+import "github.com/alecthomas/participle/lexer"
+
+// Script is a sequence of statements separated by semicolons, such as a migration file.
+type Script struct {
+	Statements []*ScriptStatement `";"* @@ ( ";"+ @@ )* ";"*`
+}
+
+func (s *Script) children() (children []Node) {
+	for _, stmt := range s.Statements {
+		children = append(children, stmt)
+	}
+	return
+}
+
+// ScriptStatement is a statement of a Script, with its position in the script.
+type ScriptStatement struct {
+	Pos lexer.Position
+
+	AST *AST `@@`
+}
+
+func (s *ScriptStatement) children() []Node { return []Node{s.AST} }
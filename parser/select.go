@@ -8,16 +8,24 @@ import (
 
 // Select based on http://www.h2database.com/html/grammar.html
 type Select struct {
-	Projection *ProjectionExpression `"SELECT" @@`
+	Scan       bool                  `( "SELECT" | @"SCAN" )`
+	Hint       *string               `@Hint?` // An optimizer hint such as /*+ ... */. Not used yet.
+	Projection *ProjectionExpression `@@`
 	From       string                `"FROM" @( Ident ( "." Ident )* | QuotedIdent )`
 	Index      *string               `( "USE" "INDEX" "(" @Ident ")" )?`
-	Where      *AndExpression        `( "WHERE" @@ )?`
+	Where      *AndExpression        `( "WHERE" @@`
+	Or         []*AndExpression      `  ( "OR" @@ )* )?`
 	Descending *ScanDescending       `( @"ASC" | @"DESC" )?`
 	Limit      *int                  `( "LIMIT" @Number )?`
+	Segments   *int                  `( "SEGMENTS" @Number )?`
 }
 
 func (e *Select) children() (children []Node) {
-	return []Node{e.Projection, e.Where}
+	children = []Node{e.Projection, e.Where}
+	for _, or := range e.Or {
+		children = append(children, or)
+	}
+	return
 }
 
 type ProjectionExpression struct {
@@ -87,14 +95,39 @@ func (e *AndExpression) children() (children []Node) {
 }
 
 type Condition struct {
-	Parenthesized *ConditionExpression `  "(" @@ ")"`
+	TupleIn       *TupleIn             `  @@`
+	Parenthesized *ConditionExpression `| "(" @@ ")"`
 	Not           *NotCondition        `| "NOT" @@`
 	Operand       *ConditionOperand    `| @@`
 	Function      *FunctionExpression  `| @@`
 }
 
 func (e *Condition) children() (children []Node) {
-	return []Node{e.Parenthesized, e.Not, e.Operand, e.Function}
+	return []Node{e.TupleIn, e.Parenthesized, e.Not, e.Operand, e.Function}
+}
+
+// TupleIn matches a tuple of attributes against a list of tuples of values, such as (a, b) IN ((1, 2), (3, 4)).
+type TupleIn struct {
+	Operands []string `"(" @( Ident | QuotedIdent ) ( "," @( Ident | QuotedIdent ) )+ ")" "IN"`
+	Values   []*Tuple `"(" @@ ( "," @@ )* ")"`
+}
+
+func (t *TupleIn) children() (children []Node) {
+	for _, value := range t.Values {
+		children = append(children, value)
+	}
+	return
+}
+
+type Tuple struct {
+	Values []*Value `"(" @@ ( "," @@ )* ")"`
+}
+
+func (t *Tuple) children() (children []Node) {
+	for _, value := range t.Values {
+		children = append(children, value)
+	}
+	return
 }
 
 type NotCondition struct {
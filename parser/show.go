@@ -0,0 +1,28 @@
+// nolint: govet
+package parser
+
+// ShowTables lists tables, optionally only those with names that match a LIKE pattern.
+type ShowTables struct {
+	Like *string `"SHOW" "TABLES" ( "LIKE" @String )?`
+}
+
+func (s *ShowTables) children() []Node { return nil }
+
+type ShowIndexes struct {
+	Table string `"SHOW" "INDEXES" "FROM" @( Ident ( "." Ident )* | QuotedIdent )`
+}
+
+func (s *ShowIndexes) children() []Node { return nil }
+
+type Describe struct {
+	Table string `"DESCRIBE" @( Ident ( "." Ident )* | QuotedIdent )`
+}
+
+func (d *Describe) children() []Node { return nil }
+
+// ShowCreateTable returns the CREATE TABLE statement that creates an existing table.
+type ShowCreateTable struct {
+	Table string `"SHOW" "CREATE" "TABLE" @( Ident ( "." Ident )* | QuotedIdent )`
+}
+
+func (s *ShowCreateTable) children() []Node { return nil }
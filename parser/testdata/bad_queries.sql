@@ -1,2 +1,3 @@
-SELECT * FROM movies WHERE title = "hello" OR title = "world"
-SELECT title, year FROM movies WHERE title = "The Dark Knight" AND year BETWEEN 2009 AND 2015 OR actor = "Will Smith"
+SELECT * FROM movies WHERE title = "hello" OR
+SELECT * FROM movies OR title = "world"
+SELECT * FROM movies /* unterminated
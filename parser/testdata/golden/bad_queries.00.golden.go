@@ -1,4 +1,4 @@
 {
-  "Query": "SELECT * FROM movies WHERE title = \"hello\" OR title = \"world\"",
-  "Error": "1:44: unexpected token \"OR\""
+  "Query": "SELECT * FROM movies WHERE title = \"hello\" OR",
+  "Error": "1:46: unexpected token \"<EOF>\" (expected \"(\" | \"(\" | \"NOT\" | <ident> | <quotedident> | <ident>)"
 }
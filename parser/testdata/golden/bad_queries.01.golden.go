@@ -1,4 +1,4 @@
 {
-  "Query": "SELECT title, year FROM movies WHERE title = \"The Dark Knight\" AND year BETWEEN 2009 AND 2015 OR actor = \"Will Smith\"",
-  "Error": "1:95: unexpected token \"OR\""
+  "Query": "SELECT * FROM movies OR title = \"world\"",
+  "Error": "1:22: unexpected token \"OR\""
 }
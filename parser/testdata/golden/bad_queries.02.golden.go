@@ -0,0 +1,4 @@
+{
+  "Query": "SELECT * FROM movies /* unterminated",
+  "Error": "1:22: unexpected token \"/\""
+}
@@ -0,0 +1,103 @@
+parser.row{
+  Query: "UPDATE movies SET rating = :rating, info.actors[2] = 'Tom Hanks' REMOVE plot WHERE title = :title AND year = :year",
+  AST: &parser.AST{
+    Update: &parser.Update{
+      Table: "movies",
+      Set: []*parser.SetExpression{
+        {
+          Path: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "rating",
+              },
+            },
+          },
+          Value: &parser.Value{
+            Scalar: parser.Scalar{
+            },
+            PlaceHolder: &":rating",
+          },
+        },
+        {
+          Path: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "info",
+              },
+              {
+                Symbol: "actors",
+                Indexes: []int{
+                  2,
+                },
+              },
+            },
+          },
+          Value: &parser.Value{
+            Scalar: parser.Scalar{
+              Str: &"Tom Hanks",
+            },
+          },
+        },
+      },
+      Remove: []*parser.DocumentPath{
+        {
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "plot",
+            },
+          },
+        },
+      },
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "title",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PlaceHolder: &":title",
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "year",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PlaceHolder: &":year",
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,94 @@
+parser.row{
+  Query: "UPDATE movies REMOVE plot, info.rating WHERE title = ? AND year = ? AND attribute_exists(plot) RETURNING ALL_OLD",
+  AST: &parser.AST{
+    Update: &parser.Update{
+      Table: "movies",
+      Remove: []*parser.DocumentPath{
+        {
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "plot",
+            },
+          },
+        },
+        {
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "info",
+            },
+            {
+              Symbol: "rating",
+            },
+          },
+        },
+      },
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "title",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PositionalPlaceholder: true,
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "year",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PositionalPlaceholder: true,
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            Function: &parser.FunctionExpression{
+              Function: "attribute_exists",
+              Args: []*parser.FunctionArgument{
+                {
+                  DocumentPath: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "plot",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Returning: &"ALL_OLD",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,128 @@
+parser.row{
+  Query: "UPDATE movies SET rating = 5 WHERE title = \"Big\" AND year = 1988 AND (rating < 5 OR attribute_not_exists(rating)) RETURNING ALL_NEW",
+  AST: &parser.AST{
+    Update: &parser.Update{
+      Table: "movies",
+      Set: []*parser.SetExpression{
+        {
+          Path: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "rating",
+              },
+            },
+          },
+          Value: &parser.Value{
+            Scalar: parser.Scalar{
+              Number: &5,
+            },
+          },
+        },
+      },
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "title",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                        Str: &"Big",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "year",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                        Number: &1988,
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            Parenthesized: &parser.ConditionExpression{
+              Or: []*parser.AndExpression{
+                {
+                  And: []*parser.Condition{
+                    {
+                      Operand: &parser.ConditionOperand{
+                        Operand: &parser.DocumentPath{
+                          Fragment: []*parser.PathFragment{
+                            {
+                              Symbol: "rating",
+                            },
+                          },
+                        },
+                        ConditionRHS: &parser.ConditionRHS{
+                          Compare: &parser.Compare{
+                            Operator: "<",
+                            Operand: &parser.Operand{
+                              Value: &parser.Value{
+                                Scalar: parser.Scalar{
+                                  Number: &5,
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+                {
+                  And: []*parser.Condition{
+                    {
+                      Function: &parser.FunctionExpression{
+                        Function: "attribute_not_exists",
+                        Args: []*parser.FunctionArgument{
+                          {
+                            DocumentPath: &parser.DocumentPath{
+                              Fragment: []*parser.PathFragment{
+                                {
+                                  Symbol: "rating",
+                                },
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Returning: &"ALL_NEW",
+    },
+  },
+}
\ No newline at end of file
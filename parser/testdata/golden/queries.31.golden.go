@@ -0,0 +1,58 @@
+parser.row{
+  Query: "DELETE FROM movies WHERE title = :title AND year = :year",
+  AST: &parser.AST{
+    Delete: &parser.Delete{
+      Table: "movies",
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "title",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PlaceHolder: &":title",
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "year",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PlaceHolder: &":year",
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,85 @@
+parser.row{
+  Query: "DELETE FROM movies WHERE title = ? AND year = ? AND info.rating < ? RETURNING ALL_OLD",
+  AST: &parser.AST{
+    Delete: &parser.Delete{
+      Table: "movies",
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "title",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PositionalPlaceholder: true,
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "year",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PositionalPlaceholder: true,
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "info",
+                  },
+                  {
+                    Symbol: "rating",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "<",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PositionalPlaceholder: true,
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Returning: &"ALL_OLD",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,12 @@
+parser.row{
+  Query: "SCAN * FROM movies",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Scan: true,
+      Projection: &parser.ProjectionExpression{
+        All: true,
+      },
+      From: "movies",
+    },
+  },
+}
\ No newline at end of file
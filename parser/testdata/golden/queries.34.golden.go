@@ -0,0 +1,66 @@
+parser.row{
+  Query: "SCAN title, info.rating FROM movies USE INDEX (rating_index) WHERE info.rating > 8 LIMIT 10",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Scan: true,
+      Projection: &parser.ProjectionExpression{
+        Columns: []*parser.ProjectionColumn{
+          {
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "title",
+                },
+              },
+            },
+          },
+          {
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "info",
+                },
+                {
+                  Symbol: "rating",
+                },
+              },
+            },
+          },
+        },
+      },
+      From: "movies",
+      Index: &"rating_index",
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "info",
+                  },
+                  {
+                    Symbol: "rating",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: ">",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                        Number: &8,
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Limit: &10,
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,43 @@
+parser.row{
+  Query: "SCAN * FROM movies WHERE info.rating > 8 SEGMENTS 8",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Scan: true,
+      Projection: &parser.ProjectionExpression{
+        All: true,
+      },
+      From: "movies",
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "info",
+                  },
+                  {
+                    Symbol: "rating",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: ">",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                        Number: &8,
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Segments: &8,
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,67 @@
+parser.row{
+  Query: "SELECT * FROM movies WHERE title = \"hello\" OR title = \"world\"",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        All: true,
+      },
+      From: "movies",
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "title",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                        Str: &"hello",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Or: []*parser.AndExpression{
+        {
+          And: []*parser.Condition{
+            {
+              Operand: &parser.ConditionOperand{
+                Operand: &parser.DocumentPath{
+                  Fragment: []*parser.PathFragment{
+                    {
+                      Symbol: "title",
+                    },
+                  },
+                },
+                ConditionRHS: &parser.ConditionRHS{
+                  Compare: &parser.Compare{
+                    Operator: "=",
+                    Operand: &parser.Operand{
+                      Value: &parser.Value{
+                        Scalar: parser.Scalar{
+                          Str: &"world",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
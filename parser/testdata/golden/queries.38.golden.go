@@ -0,0 +1,67 @@
+parser.row{
+  Query: "SELECT * FROM movies WHERE title IN (?, ?) AND year > 2000 DESC LIMIT 10",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        All: true,
+      },
+      From: "movies",
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "title",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                In: &parser.In{
+                  Values: []*parser.Value{
+                    {
+                      Scalar: parser.Scalar{
+                      },
+                      PositionalPlaceholder: true,
+                    },
+                    {
+                      Scalar: parser.Scalar{
+                      },
+                      PositionalPlaceholder: true,
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "year",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: ">",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                        Number: &2000,
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Descending: &parser.ScanDescending(true),
+      Limit: &10,
+    },
+  },
+}
\ No newline at end of file
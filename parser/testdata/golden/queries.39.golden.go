@@ -0,0 +1,53 @@
+parser.row{
+  Query: "SELECT * FROM movies WHERE (title, year) IN ((?, ?), (\"Big\", 1988))",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        All: true,
+      },
+      From: "movies",
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            TupleIn: &parser.TupleIn{
+              Operands: []string{
+                "title",
+                "year",
+              },
+              Values: []*parser.Tuple{
+                {
+                  Values: []*parser.Value{
+                    {
+                      Scalar: parser.Scalar{
+                      },
+                      PositionalPlaceholder: true,
+                    },
+                    {
+                      Scalar: parser.Scalar{
+                      },
+                      PositionalPlaceholder: true,
+                    },
+                  },
+                },
+                {
+                  Values: []*parser.Value{
+                    {
+                      Scalar: parser.Scalar{
+                        Str: &"Big",
+                      },
+                    },
+                    {
+                      Scalar: parser.Scalar{
+                        Number: &1988,
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,74 @@
+parser.row{
+  Query: "CHECK accounts WHERE id = :id AND attribute_exists(owner) AND status = 'open'",
+  AST: &parser.AST{
+    Check: &parser.Check{
+      Table: "accounts",
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "id",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PlaceHolder: &":id",
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            Function: &parser.FunctionExpression{
+              Function: "attribute_exists",
+              Args: []*parser.FunctionArgument{
+                {
+                  DocumentPath: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "owner",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "status",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                        Str: &"open",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,19 @@
+parser.row{
+  Query: "REPLACE INTO movies VALUES (?) NOT ATOMIC",
+  AST: &parser.AST{
+    Insert: &parser.InsertOrReplace{
+      Replace: true,
+      Into: "movies",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+            PositionalPlaceholder: true,
+          },
+        },
+      },
+      NotAtomic: true,
+    },
+  },
+}
\ No newline at end of file
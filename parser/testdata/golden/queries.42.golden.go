@@ -0,0 +1,30 @@
+parser.row{
+  Query: "ALTER TABLE movies ADD director STRING, ADD GLOBAL INDEX director_year HASH(director) RANGE(year) PROJECTION KEYS_ONLY PROVISIONED THROUGHPUT READ 1 WRITE 1",
+  AST: &parser.AST{
+    AlterTable: &parser.AlterTable{
+      Table: "movies",
+      Actions: []*parser.AlterTableAction{
+        {
+          AddAttr: &parser.TableAttr{
+            Name: "director",
+            Type: "STRING",
+          },
+        },
+        {
+          AddIndex: &parser.GlobalSecondaryIndex{
+            Name: "director_year",
+            PartitionKey: "director",
+            SortKey: "year",
+            Projection: &parser.Projection{
+              KeysOnly: true,
+            },
+            ProvisionedThroughput: &parser.ProvisionedThroughput{
+              ReadCapacityUnits: 1,
+              WriteCapacityUnits: 1,
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,25 @@
+parser.row{
+  Query: "ALTER TABLE movies SET PROVISIONED THROUGHPUT READ 5 WRITE 5, ALTER INDEX year_title SET PROVISIONED THROUGHPUT READ 2 WRITE 2",
+  AST: &parser.AST{
+    AlterTable: &parser.AlterTable{
+      Table: "movies",
+      Actions: []*parser.AlterTableAction{
+        {
+          ProvisionedThroughput: &parser.ProvisionedThroughput{
+            ReadCapacityUnits: 5,
+            WriteCapacityUnits: 5,
+          },
+        },
+        {
+          AlterIndex: &parser.AlterIndex{
+            Name: "year_title",
+            ProvisionedThroughput: &parser.ProvisionedThroughput{
+              ReadCapacityUnits: 2,
+              WriteCapacityUnits: 2,
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
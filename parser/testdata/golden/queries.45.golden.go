@@ -0,0 +1,33 @@
+parser.row{
+  Query: "CREATE TABLE movies (title STRING HASH KEY, year NUMBER, GLOBAL INDEX year_index HASH(year) PROJECTION ALL) BILLING MODE PAY_PER_REQUEST",
+  AST: &parser.AST{
+    CreateTable: &parser.CreateTable{
+      Table: "movies",
+      Entries: []*parser.CreateTableEntry{
+        {
+          Attr: &parser.TableAttr{
+            Name: "title",
+            Type: "STRING",
+            Key: "HASH",
+          },
+        },
+        {
+          Attr: &parser.TableAttr{
+            Name: "year",
+            Type: "NUMBER",
+          },
+        },
+        {
+          GlobalSecondaryIndex: &parser.GlobalSecondaryIndex{
+            Name: "year_index",
+            PartitionKey: "year",
+            Projection: &parser.Projection{
+              All: true,
+            },
+          },
+        },
+      },
+      BillingMode: &"PAY_PER_REQUEST",
+    },
+  },
+}
\ No newline at end of file
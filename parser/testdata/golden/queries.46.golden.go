@@ -0,0 +1,22 @@
+parser.row{
+  Query: "CREATE TABLE movies (title STRING HASH KEY) BILLING MODE PROVISIONED PROVISIONED THROUGHPUT READ 1 WRITE 1",
+  AST: &parser.AST{
+    CreateTable: &parser.CreateTable{
+      Table: "movies",
+      Entries: []*parser.CreateTableEntry{
+        {
+          Attr: &parser.TableAttr{
+            Name: "title",
+            Type: "STRING",
+            Key: "HASH",
+          },
+        },
+      },
+      BillingMode: &"PROVISIONED",
+      ProvisionedThroughput: &parser.ProvisionedThroughput{
+        ReadCapacityUnits: 1,
+        WriteCapacityUnits: 1,
+      },
+    },
+  },
+}
\ No newline at end of file
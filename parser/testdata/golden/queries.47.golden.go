@@ -0,0 +1,63 @@
+parser.row{
+  Query: "CREATE TABLE events (id STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WITH (STREAM = NEW_AND_OLD_IMAGES, TTL = expires_at, SSE = KMS, TABLE_CLASS = STANDARD_INFREQUENT_ACCESS, DELETION_PROTECTION = TRUE, TAGS = {team: 'payments'})",
+  AST: &parser.AST{
+    CreateTable: &parser.CreateTable{
+      Table: "events",
+      Entries: []*parser.CreateTableEntry{
+        {
+          Attr: &parser.TableAttr{
+            Name: "id",
+            Type: "STRING",
+            Key: "HASH",
+          },
+        },
+      },
+      BillingMode: &"PAY_PER_REQUEST",
+      Options: []*parser.TableOption{
+        {
+          Name: "STREAM",
+          Ident: &"NEW_AND_OLD_IMAGES",
+        },
+        {
+          Name: "TTL",
+          Ident: &"expires_at",
+        },
+        {
+          Name: "SSE",
+          Ident: &"KMS",
+        },
+        {
+          Name: "TABLE_CLASS",
+          Ident: &"STANDARD_INFREQUENT_ACCESS",
+        },
+        {
+          Name: "DELETION_PROTECTION",
+          Value: &parser.JSONValue{
+            Scalar: parser.Scalar{
+              Boolean: &parser.Boolean(true),
+            },
+          },
+        },
+        {
+          Name: "TAGS",
+          Value: &parser.JSONValue{
+            Scalar: parser.Scalar{
+            },
+            Object: &parser.JSONObject{
+              Entries: []*parser.JSONObjectEntry{
+                {
+                  Key: "team",
+                  Value: &parser.JSONValue{
+                    Scalar: parser.Scalar{
+                      Str: &"payments",
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,20 @@
+parser.row{
+  Query: "CREATE TABLE IF NOT EXISTS movies (title STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WAIT",
+  AST: &parser.AST{
+    CreateTable: &parser.CreateTable{
+      IfNotExists: true,
+      Table: "movies",
+      Entries: []*parser.CreateTableEntry{
+        {
+          Attr: &parser.TableAttr{
+            Name: "title",
+            Type: "STRING",
+            Key: "HASH",
+          },
+        },
+      },
+      BillingMode: &"PAY_PER_REQUEST",
+      Wait: true,
+    },
+  },
+}
\ No newline at end of file
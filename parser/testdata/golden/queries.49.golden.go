@@ -0,0 +1,10 @@
+parser.row{
+  Query: "DROP TABLE IF EXISTS movies WAIT",
+  AST: &parser.AST{
+    DropTable: &parser.DropTable{
+      IfExists: true,
+      Table: "movies",
+      Wait: true,
+    },
+  },
+}
\ No newline at end of file
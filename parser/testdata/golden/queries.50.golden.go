@@ -0,0 +1,14 @@
+parser.row{
+  Query: "ALTER TABLE movies DROP INDEX year_title WAIT",
+  AST: &parser.AST{
+    AlterTable: &parser.AlterTable{
+      Table: "movies",
+      Actions: []*parser.AlterTableAction{
+        {
+          DropIndex: &"year_title",
+        },
+      },
+      Wait: true,
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,23 @@
+parser.row{
+  Query: "CREATE GLOBAL INDEX director_year ON movies HASH(director STRING) RANGE(year) PROJECTION INCLUDE (rating, plot) WAIT",
+  AST: &parser.AST{
+    CreateIndex: &parser.CreateIndex{
+      Name: "director_year",
+      Table: "movies",
+      PartitionKey: &parser.IndexKey{
+        Name: "director",
+        Type: "STRING",
+      },
+      SortKey: &parser.IndexKey{
+        Name: "year",
+      },
+      Projection: &parser.Projection{
+        Include: []string{
+          "rating",
+          "plot",
+        },
+      },
+      Wait: true,
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,9 @@
+parser.row{
+  Query: "DROP INDEX director_year ON movies",
+  AST: &parser.AST{
+    DropIndex: &parser.DropIndex{
+      Name: "director_year",
+      Table: "movies",
+    },
+  },
+}
\ No newline at end of file
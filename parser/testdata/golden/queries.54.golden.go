@@ -0,0 +1,8 @@
+parser.row{
+  Query: "SHOW TABLES LIKE 'movie%'",
+  AST: &parser.AST{
+    ShowTables: &parser.ShowTables{
+      Like: &"movie%",
+    },
+  },
+}
\ No newline at end of file
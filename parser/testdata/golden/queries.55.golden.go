@@ -0,0 +1,8 @@
+parser.row{
+  Query: "SHOW INDEXES FROM gamescores",
+  AST: &parser.AST{
+    ShowIndexes: &parser.ShowIndexes{
+      Table: "gamescores",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,8 @@
+parser.row{
+  Query: "SHOW CREATE TABLE movies",
+  AST: &parser.AST{
+    ShowCreate: &parser.ShowCreateTable{
+      Table: "movies",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+parser.row{
+  Query: "EXPLAIN SELECT * FROM movies WHERE title = :title AND rating > 5 LIMIT 10",
+  AST: &parser.AST{
+    Explain: &parser.Explain{
+      Statement: &parser.AST{
+        Select: &parser.Select{
+          Projection: &parser.ProjectionExpression{
+            All: true,
+          },
+          From: "movies",
+          Where: &parser.AndExpression{
+            And: []*parser.Condition{
+              {
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PlaceHolder: &":title",
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "rating",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: ">",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                            Number: &5,
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+          Limit: &10,
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
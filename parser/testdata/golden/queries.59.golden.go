@@ -0,0 +1,78 @@
+parser.row{
+  Query: "EXPLAIN UPDATE movies SET rating = ? WHERE title = ? AND year = ?;",
+  AST: &parser.AST{
+    Explain: &parser.Explain{
+      Statement: &parser.AST{
+        Update: &parser.Update{
+          Table: "movies",
+          Set: []*parser.SetExpression{
+            {
+              Path: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "rating",
+                  },
+                },
+              },
+              Value: &parser.Value{
+                Scalar: parser.Scalar{
+                },
+                PositionalPlaceholder: true,
+              },
+            },
+          },
+          Where: &parser.AndExpression{
+            And: []*parser.Condition{
+              {
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PositionalPlaceholder: true,
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Operand: &parser.ConditionOperand{
+                  Operand: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "year",
+                      },
+                    },
+                  },
+                  ConditionRHS: &parser.ConditionRHS{
+                    Compare: &parser.Compare{
+                      Operator: "=",
+                      Operand: &parser.Operand{
+                        Value: &parser.Value{
+                          Scalar: parser.Scalar{
+                          },
+                          PositionalPlaceholder: true,
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
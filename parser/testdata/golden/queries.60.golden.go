@@ -0,0 +1,21 @@
+parser.row{
+  Query: "SELECT title /* the title */ FROM movies -- only the title",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Projection: &parser.ProjectionExpression{
+        Columns: []*parser.ProjectionColumn{
+          {
+            DocumentPath: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "title",
+                },
+              },
+            },
+          },
+        },
+      },
+      From: "movies",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,39 @@
+parser.row{
+  Query: "SELECT /*+ CONSISTENT */ * FROM movies WHERE title = /**/ 'Big' /* AND year = 1988 */",
+  AST: &parser.AST{
+    Select: &parser.Select{
+      Hint: &"/*+ CONSISTENT */",
+      Projection: &parser.ProjectionExpression{
+        All: true,
+      },
+      From: "movies",
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "title",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                        Str: &"Big",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,211 @@
+parser.row{
+  Query: "UPDATE movies SET views = views + :n, tags = list_append(if_not_exists(tags, :empty), :new), created = if_not_exists(created, :now), rank = rank-1 ADD visitors :visitors DELETE editors ? WHERE title = :title AND year = :year",
+  AST: &parser.AST{
+    Update: &parser.Update{
+      Table: "movies",
+      Set: []*parser.SetExpression{
+        {
+          Path: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "views",
+              },
+            },
+          },
+          Operand: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "views",
+              },
+            },
+          },
+          Arithmetic: &parser.SetArithmetic{
+            Operator: "+",
+            Operand: &parser.SetOperand{
+              Value: &parser.Value{
+                Scalar: parser.Scalar{
+                },
+                PlaceHolder: &":n",
+              },
+            },
+          },
+        },
+        {
+          Path: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "tags",
+              },
+            },
+          },
+          Function: &parser.SetFunction{
+            Function: "list_append",
+            Args: []*parser.SetOperand{
+              {
+                Function: &parser.SetFunction{
+                  Function: "if_not_exists",
+                  Args: []*parser.SetOperand{
+                    {
+                      Path: &parser.DocumentPath{
+                        Fragment: []*parser.PathFragment{
+                          {
+                            Symbol: "tags",
+                          },
+                        },
+                      },
+                    },
+                    {
+                      Value: &parser.Value{
+                        Scalar: parser.Scalar{
+                        },
+                        PlaceHolder: &":empty",
+                      },
+                    },
+                  },
+                },
+              },
+              {
+                Value: &parser.Value{
+                  Scalar: parser.Scalar{
+                  },
+                  PlaceHolder: &":new",
+                },
+              },
+            },
+          },
+        },
+        {
+          Path: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "created",
+              },
+            },
+          },
+          Function: &parser.SetFunction{
+            Function: "if_not_exists",
+            Args: []*parser.SetOperand{
+              {
+                Path: &parser.DocumentPath{
+                  Fragment: []*parser.PathFragment{
+                    {
+                      Symbol: "created",
+                    },
+                  },
+                },
+              },
+              {
+                Value: &parser.Value{
+                  Scalar: parser.Scalar{
+                  },
+                  PlaceHolder: &":now",
+                },
+              },
+            },
+          },
+        },
+        {
+          Path: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "rank",
+              },
+            },
+          },
+          Operand: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "rank",
+              },
+            },
+          },
+          Arithmetic: &parser.SetArithmetic{
+            Signed: &"-1",
+          },
+        },
+      },
+      Add: []*parser.PathValue{
+        {
+          Path: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "visitors",
+              },
+            },
+          },
+          Value: &parser.Value{
+            Scalar: parser.Scalar{
+            },
+            PlaceHolder: &":visitors",
+          },
+        },
+      },
+      Delete: []*parser.PathValue{
+        {
+          Path: &parser.DocumentPath{
+            Fragment: []*parser.PathFragment{
+              {
+                Symbol: "editors",
+              },
+            },
+          },
+          Value: &parser.Value{
+            Scalar: parser.Scalar{
+            },
+            PositionalPlaceholder: true,
+          },
+        },
+      },
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "title",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PlaceHolder: &":title",
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "year",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PlaceHolder: &":year",
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,62 @@
+parser.row{
+  Query: "REPLACE INTO movies VALUES (:doc) WHERE version = :expected AND attribute_exists(title) RETURNING ALL_OLD",
+  AST: &parser.AST{
+    Insert: &parser.InsertOrReplace{
+      Replace: true,
+      Into: "movies",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+            PlaceHolder: &":doc",
+          },
+        },
+      },
+      Where: &parser.AndExpression{
+        And: []*parser.Condition{
+          {
+            Operand: &parser.ConditionOperand{
+              Operand: &parser.DocumentPath{
+                Fragment: []*parser.PathFragment{
+                  {
+                    Symbol: "version",
+                  },
+                },
+              },
+              ConditionRHS: &parser.ConditionRHS{
+                Compare: &parser.Compare{
+                  Operator: "=",
+                  Operand: &parser.Operand{
+                    Value: &parser.Value{
+                      Scalar: parser.Scalar{
+                      },
+                      PlaceHolder: &":expected",
+                    },
+                  },
+                },
+              },
+            },
+          },
+          {
+            Function: &parser.FunctionExpression{
+              Function: "attribute_exists",
+              Args: []*parser.FunctionArgument{
+                {
+                  DocumentPath: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "title",
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      Returning: &"ALL_OLD",
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,65 @@
+parser.row{
+  Query: "INSERT INTO movies VALUES ({\"title\": \"Big\", \"year\": 1988}), ({\"title\": \"Up\", \"year\": 2009}) ON CONFLICT DO NOTHING",
+  AST: &parser.AST{
+    Insert: &parser.InsertOrReplace{
+      Into: "movies",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+          },
+          Object: &parser.JSONObject{
+            Entries: []*parser.JSONObjectEntry{
+              {
+                Key: "title",
+                Value: &parser.JSONValue{
+                  Scalar: parser.Scalar{
+                    Str: &"Big",
+                  },
+                },
+              },
+              {
+                Key: "year",
+                Value: &parser.JSONValue{
+                  Scalar: parser.Scalar{
+                    Number: &1988,
+                  },
+                },
+              },
+            },
+          },
+        },
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+          },
+          Object: &parser.JSONObject{
+            Entries: []*parser.JSONObjectEntry{
+              {
+                Key: "title",
+                Value: &parser.JSONValue{
+                  Scalar: parser.Scalar{
+                    Str: &"Up",
+                  },
+                },
+              },
+              {
+                Key: "year",
+                Value: &parser.JSONValue{
+                  Scalar: parser.Scalar{
+                    Number: &2009,
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      OnConflict: &parser.OnConflict{
+        DoNothing: true,
+      },
+    },
+  },
+}
\ No newline at end of file
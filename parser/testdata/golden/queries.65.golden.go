@@ -0,0 +1,97 @@
+parser.row{
+  Query: "INSERT INTO movies VALUES (:movie) ON CONFLICT DO UPDATE SET views = if_not_exists(views, 0) + 1, info.genres = list_append(info.genres, :genres)",
+  AST: &parser.AST{
+    Insert: &parser.InsertOrReplace{
+      Into: "movies",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+            PlaceHolder: &":movie",
+          },
+        },
+      },
+      OnConflict: &parser.OnConflict{
+        Update: []*parser.SetExpression{
+          {
+            Path: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "views",
+                },
+              },
+            },
+            Function: &parser.SetFunction{
+              Function: "if_not_exists",
+              Args: []*parser.SetOperand{
+                {
+                  Path: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "views",
+                      },
+                    },
+                  },
+                },
+                {
+                  Value: &parser.Value{
+                    Scalar: parser.Scalar{
+                      Number: &0,
+                    },
+                  },
+                },
+              },
+            },
+            Arithmetic: &parser.SetArithmetic{
+              Operator: "+",
+              Operand: &parser.SetOperand{
+                Value: &parser.Value{
+                  Scalar: parser.Scalar{
+                    Number: &1,
+                  },
+                },
+              },
+            },
+          },
+          {
+            Path: &parser.DocumentPath{
+              Fragment: []*parser.PathFragment{
+                {
+                  Symbol: "info",
+                },
+                {
+                  Symbol: "genres",
+                },
+              },
+            },
+            Function: &parser.SetFunction{
+              Function: "list_append",
+              Args: []*parser.SetOperand{
+                {
+                  Path: &parser.DocumentPath{
+                    Fragment: []*parser.PathFragment{
+                      {
+                        Symbol: "info",
+                      },
+                      {
+                        Symbol: "genres",
+                      },
+                    },
+                  },
+                },
+                {
+                  Value: &parser.Value{
+                    Scalar: parser.Scalar{
+                    },
+                    PlaceHolder: &":genres",
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,65 @@
+parser.row{
+  Query: "INSERT INTO movies (title, year, actors) VALUES (?, ?, ?), (?, ?, ?)",
+  AST: &parser.AST{
+    Insert: &parser.InsertOrReplace{
+      Into: "movies",
+      Columns: []string{
+        "title",
+        "year",
+        "actors",
+      },
+      Rows: []*parser.InsertRow{
+        {
+          Values: []*parser.InsertTerminal{
+            {
+              Value: parser.Value{
+                Scalar: parser.Scalar{
+                },
+                PositionalPlaceholder: true,
+              },
+            },
+            {
+              Value: parser.Value{
+                Scalar: parser.Scalar{
+                },
+                PositionalPlaceholder: true,
+              },
+            },
+            {
+              Value: parser.Value{
+                Scalar: parser.Scalar{
+                },
+                PositionalPlaceholder: true,
+              },
+            },
+          },
+        },
+        {
+          Values: []*parser.InsertTerminal{
+            {
+              Value: parser.Value{
+                Scalar: parser.Scalar{
+                },
+                PositionalPlaceholder: true,
+              },
+            },
+            {
+              Value: parser.Value{
+                Scalar: parser.Scalar{
+                },
+                PositionalPlaceholder: true,
+              },
+            },
+            {
+              Value: parser.Value{
+                Scalar: parser.Scalar{
+                },
+                PositionalPlaceholder: true,
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,70 @@
+parser.row{
+  Query: "INSERT INTO movies (title, `year`, info, actors) VALUES (:title, 1988, {\"rating\": 7}, [\"Tom Hanks\"]) ON CONFLICT DO NOTHING",
+  AST: &parser.AST{
+    Insert: &parser.InsertOrReplace{
+      Into: "movies",
+      Columns: []string{
+        "title",
+        "year",
+        "info",
+        "actors",
+      },
+      Rows: []*parser.InsertRow{
+        {
+          Values: []*parser.InsertTerminal{
+            {
+              Value: parser.Value{
+                Scalar: parser.Scalar{
+                },
+                PlaceHolder: &":title",
+              },
+            },
+            {
+              Value: parser.Value{
+                Scalar: parser.Scalar{
+                  Number: &1988,
+                },
+              },
+            },
+            {
+              Value: parser.Value{
+                Scalar: parser.Scalar{
+                },
+              },
+              Object: &parser.JSONObject{
+                Entries: []*parser.JSONObjectEntry{
+                  {
+                    Key: "rating",
+                    Value: &parser.JSONValue{
+                      Scalar: parser.Scalar{
+                        Number: &7,
+                      },
+                    },
+                  },
+                },
+              },
+            },
+            {
+              Value: parser.Value{
+                Scalar: parser.Scalar{
+                },
+              },
+              Array: &parser.JSONArray{
+                Entries: []*parser.JSONValue{
+                  {
+                    Scalar: parser.Scalar{
+                      Str: &"Tom Hanks",
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+      OnConflict: &parser.OnConflict{
+        DoNothing: true,
+      },
+    },
+  },
+}
\ No newline at end of file
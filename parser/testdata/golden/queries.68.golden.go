@@ -0,0 +1,68 @@
+parser.row{
+  Query: "INSERT INTO t VALUES ({\"id\": :id, \"meta\": {\"owner\": ?, \"tags\": [:t1, :t2]}})",
+  AST: &parser.AST{
+    Insert: &parser.InsertOrReplace{
+      Into: "t",
+      Values: []*parser.InsertTerminal{
+        {
+          Value: parser.Value{
+            Scalar: parser.Scalar{
+            },
+          },
+          Object: &parser.JSONObject{
+            Entries: []*parser.JSONObjectEntry{
+              {
+                Key: "id",
+                Value: &parser.JSONValue{
+                  Scalar: parser.Scalar{
+                  },
+                  PlaceHolder: &":id",
+                },
+              },
+              {
+                Key: "meta",
+                Value: &parser.JSONValue{
+                  Scalar: parser.Scalar{
+                  },
+                  Object: &parser.JSONObject{
+                    Entries: []*parser.JSONObjectEntry{
+                      {
+                        Key: "owner",
+                        Value: &parser.JSONValue{
+                          Scalar: parser.Scalar{
+                          },
+                          PositionalPlaceholder: true,
+                        },
+                      },
+                      {
+                        Key: "tags",
+                        Value: &parser.JSONValue{
+                          Scalar: parser.Scalar{
+                          },
+                          Array: &parser.JSONArray{
+                            Entries: []*parser.JSONValue{
+                              {
+                                Scalar: parser.Scalar{
+                                },
+                                PlaceHolder: &":t1",
+                              },
+                              {
+                                Scalar: parser.Scalar{
+                                },
+                                PlaceHolder: &":t2",
+                              },
+                            },
+                          },
+                        },
+                      },
+                    },
+                  },
+                },
+              },
+            },
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
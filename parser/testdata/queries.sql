@@ -41,4 +41,58 @@ CREATE TABLE movies (title STRING HASH KEY, year NUMBER RANGE KEY) PROVISIONED T
 CREATE TABLE movies (title STRING, year NUMBER, GLOBAL SECONDARY INDEX year_title HASH(year) RANGE(title) PROJECTION ALL PROVISIONED THROUGHPUT READ 1 WRITE 1) PROVISIONED THROUGHPUT READ 1 WRITE 1;
 CREATE TABLE movies (title STRING, year NUMBER, LOCAL SECONDARY INDEX year_index RANGE(year) PROJECTION ALL) PROVISIONED THROUGHPUT READ 1 WRITE 1;
 -- drop table
-DROP TABLE movies;
\ No newline at end of file
+DROP TABLE movies;
+-- update
+UPDATE movies SET rating = :rating, info.actors[2] = 'Tom Hanks' REMOVE plot WHERE title = :title AND year = :year
+UPDATE movies REMOVE plot, info.rating WHERE title = ? AND year = ? AND attribute_exists(plot) RETURNING ALL_OLD
+UPDATE movies SET rating = 5 WHERE title = "Big" AND year = 1988 AND (rating < 5 OR attribute_not_exists(rating)) RETURNING ALL_NEW
+-- delete
+DELETE FROM movies WHERE title = :title AND year = :year
+DELETE FROM movies WHERE title = ? AND year = ? AND info.rating < ? RETURNING ALL_OLD
+-- scan
+SCAN * FROM movies
+SCAN title, info.rating FROM movies USE INDEX (rating_index) WHERE info.rating > 8 LIMIT 10
+SCAN * FROM movies WHERE info.rating > 8 SEGMENTS 8
+-- partition key fan out
+SELECT * FROM movies WHERE title = "hello" OR title = "world"
+SELECT title, year FROM movies WHERE title = "The Dark Knight" AND year BETWEEN 2009 AND 2015 OR actor = "Will Smith"
+SELECT * FROM movies WHERE title IN (?, ?) AND year > 2000 DESC LIMIT 10
+-- tuple IN
+SELECT * FROM movies WHERE (title, year) IN ((?, ?), ("Big", 1988))
+-- check
+CHECK accounts WHERE id = :id AND attribute_exists(owner) AND status = 'open'
+-- replace not atomic
+REPLACE INTO movies VALUES (?) NOT ATOMIC
+-- alter table
+ALTER TABLE movies ADD director STRING, ADD GLOBAL INDEX director_year HASH(director) RANGE(year) PROJECTION KEYS_ONLY PROVISIONED THROUGHPUT READ 1 WRITE 1
+ALTER TABLE movies DROP INDEX director_year
+ALTER TABLE movies SET PROVISIONED THROUGHPUT READ 5 WRITE 5, ALTER INDEX year_title SET PROVISIONED THROUGHPUT READ 2 WRITE 2
+-- billing mode
+CREATE TABLE movies (title STRING HASH KEY, year NUMBER, GLOBAL INDEX year_index HASH(year) PROJECTION ALL) BILLING MODE PAY_PER_REQUEST
+CREATE TABLE movies (title STRING HASH KEY) BILLING MODE PROVISIONED PROVISIONED THROUGHPUT READ 1 WRITE 1
+-- table options
+CREATE TABLE events (id STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WITH (STREAM = NEW_AND_OLD_IMAGES, TTL = expires_at, SSE = KMS, TABLE_CLASS = STANDARD_INFREQUENT_ACCESS, DELETION_PROTECTION = TRUE, TAGS = {team: 'payments'})
+-- idempotent ddl
+CREATE TABLE IF NOT EXISTS movies (title STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WAIT
+DROP TABLE IF EXISTS movies WAIT
+ALTER TABLE movies DROP INDEX year_title WAIT
+-- create and drop index
+CREATE GLOBAL INDEX director_year ON movies HASH(director STRING) RANGE(year) PROJECTION INCLUDE (rating, plot) WAIT
+DROP INDEX director_year ON movies
+-- introspection
+SHOW TABLES
+SHOW TABLES LIKE 'movie%'
+SHOW INDEXES FROM gamescores
+DESCRIBE movies
+SHOW CREATE TABLE movies
+EXPLAIN SELECT * FROM movies WHERE title = :title AND rating > 5 LIMIT 10
+EXPLAIN UPDATE movies SET rating = ? WHERE title = ? AND year = ?;
+SELECT title /* the title */ FROM movies -- only the title
+SELECT /*+ CONSISTENT */ * FROM movies WHERE title = /**/ 'Big' /* AND year = 1988 */
+UPDATE movies SET views = views + :n, tags = list_append(if_not_exists(tags, :empty), :new), created = if_not_exists(created, :now), rank = rank-1 ADD visitors :visitors DELETE editors ? WHERE title = :title AND year = :year
+REPLACE INTO movies VALUES (:doc) WHERE version = :expected AND attribute_exists(title) RETURNING ALL_OLD
+INSERT INTO movies VALUES ({"title": "Big", "year": 1988}), ({"title": "Up", "year": 2009}) ON CONFLICT DO NOTHING
+INSERT INTO movies VALUES (:movie) ON CONFLICT DO UPDATE SET views = if_not_exists(views, 0) + 1, info.genres = list_append(info.genres, :genres)
+INSERT INTO movies (title, year, actors) VALUES (?, ?, ?), (?, ?, ?)
+INSERT INTO movies (title, `year`, info, actors) VALUES (:title, 1988, {"rating": 7}, ["Tom Hanks"]) ON CONFLICT DO NOTHING
+INSERT INTO t VALUES ({"id": :id, "meta": {"owner": ?, "tags": [:t1, :t2]}})
@@ -0,0 +1,89 @@
+// nolint: govet
+package parser
+
+type Update struct {
+	Table     string           `"UPDATE" @( Ident ( "." Ident )* | QuotedIdent )`
+	Set       []*SetExpression `( "SET" @@ ( "," @@ )* )?`
+	Remove    []*DocumentPath  `( "REMOVE" @@ ( "," @@ )* )?`
+	Add       []*PathValue     `( "ADD" @@ ( "," @@ )* )?`
+	Delete    []*PathValue     `( "DELETE" @@ ( "," @@ )* )?`
+	Where     *AndExpression   `"WHERE" @@`
+	Returning *string          `( "RETURNING" @( "NONE" | "ALL_OLD" | "UPDATED_OLD" | "ALL_NEW" | "UPDATED_NEW" ) )?`
+}
+
+func (u *Update) children() (children []Node) {
+	for _, set := range u.Set {
+		children = append(children, set)
+	}
+	for _, path := range u.Remove {
+		children = append(children, path)
+	}
+	for _, add := range u.Add {
+		children = append(children, add)
+	}
+	for _, del := range u.Delete {
+		children = append(children, del)
+	}
+	children = append(children, u.Where)
+	return
+}
+
+// SetExpression assigns a value to a path. The value may be another path, a function such as list_append, and may
+// have a number added to or subtracted from it.
+type SetExpression struct {
+	Path       *DocumentPath  `@@ "="`
+	Value      *Value         `( @@`
+	Function   *SetFunction   `| @@`
+	Operand    *DocumentPath  `| @@ )`
+	Arithmetic *SetArithmetic `@@?`
+}
+
+func (s *SetExpression) children() (children []Node) {
+	return []Node{s.Path, s.Value, s.Function, s.Operand, s.Arithmetic}
+}
+
+// SetOperand is an operand of a function or of arithmetic in SET.
+type SetOperand struct {
+	Value    *Value        `  @@`
+	Function *SetFunction  `| @@`
+	Path     *DocumentPath `| @@`
+}
+
+func (s *SetOperand) children() (children []Node) {
+	return []Node{s.Value, s.Function, s.Path}
+}
+
+// SetFunction is a function that computes a value in SET, either list_append or if_not_exists.
+type SetFunction struct {
+	Function string        `@Ident`
+	Args     []*SetOperand `"(" @@ ( "," @@ )* ")"`
+}
+
+func (f *SetFunction) children() (children []Node) {
+	for _, arg := range f.Args {
+		children = append(children, arg)
+	}
+	return
+}
+
+// SetArithmetic adds to or subtracts from the value in SET.
+type SetArithmetic struct {
+	Operator string      `( @( "+" | "-" )`
+	Operand  *SetOperand `  @@`
+	// A number written right after the operator, as in views+1, is lexed with its sign.
+	Signed *string `| @Number )`
+}
+
+func (a *SetArithmetic) children() (children []Node) {
+	return []Node{a.Operand}
+}
+
+// PathValue is a path and the value that ADD adds to it, or that DELETE deletes from it.
+type PathValue struct {
+	Path  *DocumentPath `@@`
+	Value *Value        `@@`
+}
+
+func (p *PathValue) children() (children []Node) {
+	return []Node{p.Path, p.Value}
+}
@@ -0,0 +1,156 @@
+// nolint: govet
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+type PreparedAlterTable struct {
+	Update *dynamodb.UpdateTableInput
+	Wait   bool
+	// The types of the attributes declared with ADD.
+	Attributes map[string]string
+	// The key attributes of the index being added, if any.
+	IndexKeys []string
+	tables    *schema.TableLoader
+}
+
+func PrepareAlterTable(tables *schema.TableLoader, alter *parser.AlterTable) (*PreparedAlterTable, error) {
+	p := &PreparedAlterTable{
+		Update:     &dynamodb.UpdateTableInput{TableName: &alter.Table},
+		Wait:       alter.Wait,
+		Attributes: make(map[string]string),
+		tables:     tables,
+	}
+	for _, action := range alter.Actions {
+		switch {
+		case action.AddAttr != nil:
+			attr := action.AddAttr
+			if attr.Key != "" {
+				return nil, fmt.Errorf("cannot change the key of table %q", alter.Table)
+			}
+			p.Attributes[attr.Name] = *mapAttributeDefinition(attr).AttributeType
+
+		case action.AddIndex != nil:
+			gsi := mapGlobalSecondaryIndex(action.AddIndex)
+			p.Update.GlobalSecondaryIndexUpdates = append(p.Update.GlobalSecondaryIndexUpdates, &dynamodb.GlobalSecondaryIndexUpdate{
+				Create: &dynamodb.CreateGlobalSecondaryIndexAction{
+					IndexName:             gsi.IndexName,
+					KeySchema:             gsi.KeySchema,
+					Projection:            gsi.Projection,
+					ProvisionedThroughput: gsi.ProvisionedThroughput,
+				},
+			})
+			for _, key := range gsi.KeySchema {
+				p.IndexKeys = append(p.IndexKeys, *key.AttributeName)
+			}
+
+		case action.DropIndex != nil:
+			p.Update.GlobalSecondaryIndexUpdates = append(p.Update.GlobalSecondaryIndexUpdates, &dynamodb.GlobalSecondaryIndexUpdate{
+				Delete: &dynamodb.DeleteGlobalSecondaryIndexAction{IndexName: action.DropIndex},
+			})
+
+		case action.ProvisionedThroughput != nil:
+			p.Update.ProvisionedThroughput = mapProvisionedThroughput(action.ProvisionedThroughput)
+
+		case action.AlterIndex != nil:
+			p.Update.GlobalSecondaryIndexUpdates = append(p.Update.GlobalSecondaryIndexUpdates, &dynamodb.GlobalSecondaryIndexUpdate{
+				Update: &dynamodb.UpdateGlobalSecondaryIndexAction{
+					IndexName:             &action.AlterIndex.Name,
+					ProvisionedThroughput: mapProvisionedThroughput(action.AlterIndex.ProvisionedThroughput),
+				},
+			})
+		}
+	}
+
+	// DynamoDB only allows one index to be added or dropped per UpdateTable.
+	var indexChanges int
+	for _, update := range p.Update.GlobalSecondaryIndexUpdates {
+		if update.Create != nil || update.Delete != nil {
+			indexChanges++
+		}
+	}
+	if indexChanges > 1 {
+		return nil, errors.New("ALTER TABLE may only add or drop one index at a time")
+	}
+	for name := range p.Attributes {
+		if !containsString(p.IndexKeys, name) {
+			return nil, fmt.Errorf("attribute %q must be a key of the index being added", name)
+		}
+	}
+	return p, nil
+}
+
+func (p *PreparedAlterTable) Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
+	req := *p.Update
+	if len(p.IndexKeys) > 0 {
+		defs, err := p.attributeDefinitions(ctx, dynamo)
+		if err != nil {
+			return nil, err
+		}
+		req.AttributeDefinitions = defs
+	}
+	if _, err := dynamo.UpdateTableWithContext(ctx, &req); err != nil {
+		return nil, err
+	}
+	p.tables.Invalidate(*req.TableName)
+	if p.Wait {
+		if err := waitForTable(ctx, dynamo, *req.TableName, false); err != nil {
+			return nil, err
+		}
+		// A query made while waiting may have loaded the schema before the index was active.
+		p.tables.Invalidate(*req.TableName)
+	}
+	return &DriverResult{count: 0}, nil
+}
+
+// attributeDefinitions returns the definitions of the key attributes of the index being added. Attributes that are
+// not declared with ADD must already be defined in the table.
+func (p *PreparedAlterTable) attributeDefinitions(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) ([]*dynamodb.AttributeDefinition, error) {
+	types := make(map[string]string, len(p.Attributes))
+	for name, typ := range p.Attributes {
+		types[name] = typ
+	}
+	if len(types) < len(p.IndexKeys) {
+		desc, err := dynamo.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{TableName: p.Update.TableName})
+		if err != nil {
+			return nil, err
+		}
+		for _, def := range desc.Table.AttributeDefinitions {
+			if _, ok := types[*def.AttributeName]; !ok {
+				types[*def.AttributeName] = *def.AttributeType
+			}
+		}
+	}
+	defs := make([]*dynamodb.AttributeDefinition, 0, len(p.IndexKeys))
+	for _, name := range p.IndexKeys {
+		typ, ok := types[name]
+		if !ok {
+			return nil, fmt.Errorf("type of attribute %q is unknown, since the table does not define it yet, declare it with a type such as %s STRING", name, name)
+		}
+		defs = append(defs, &dynamodb.AttributeDefinition{
+			AttributeName: aws.String(name),
+			AttributeType: aws.String(typ),
+		})
+	}
+	return defs, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package querybuilder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+// fakeUpdateTable describes the movies table, and records UpdateTable requests.
+type fakeUpdateTable struct {
+	dynamodbiface.DynamoDBAPI
+	describes int
+	reqs      []*dynamodb.UpdateTableInput
+}
+
+func (d *fakeUpdateTable) DescribeTableWithContext(ctx aws.Context, req *dynamodb.DescribeTableInput, opts ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	d.describes++
+	return &dynamodb.DescribeTableOutput{Table: &dynamodb.TableDescription{
+		TableName:            req.TableName,
+		KeySchema:            fixtures.Movies.Create.KeySchema,
+		AttributeDefinitions: fixtures.Movies.Create.AttributeDefinitions,
+	}}, nil
+}
+
+func (d *fakeUpdateTable) UpdateTableWithContext(ctx aws.Context, req *dynamodb.UpdateTableInput, opts ...request.Option) (*dynamodb.UpdateTableOutput, error) {
+	d.reqs = append(d.reqs, req)
+	return &dynamodb.UpdateTableOutput{}, nil
+}
+
+// queriedUpdateTable is an active movies table, whose schema is loaded by a query while ALTER TABLE waits for it.
+type queriedUpdateTable struct {
+	fakeUpdateTable
+	tables  *schema.TableLoader
+	queried bool
+}
+
+func (d *queriedUpdateTable) DescribeTableWithContext(ctx aws.Context, req *dynamodb.DescribeTableInput, opts ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	if !d.queried {
+		d.queried = true
+		if _, err := d.tables.Get(ctx, *req.TableName); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := d.fakeUpdateTable.DescribeTableWithContext(ctx, req, opts...)
+	resp.Table.TableStatus = aws.String(dynamodb.TableStatusActive)
+	return resp, err
+}
+
+func TestAlterTable(t *testing.T) {
+	prepareAlter := func(query string) (*PreparedAlterTable, error) {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		return PrepareAlterTable(schema.NewTableLoader(nil), ast.AlterTable)
+	}
+
+	t.Run("add index", func(t *testing.T) {
+		prepared, err := prepareAlter(`ALTER TABLE movies ADD director STRING, ADD GLOBAL INDEX director_year HASH(director) RANGE(year) PROJECTION KEYS_ONLY PROVISIONED THROUGHPUT READ 1 WRITE 2`)
+		require.NoError(t, err)
+		dynamo := &fakeUpdateTable{}
+		_, err = prepared.Do(context.Background(), dynamo, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, dynamo.describes)
+		require.Equal(t, []*dynamodb.UpdateTableInput{{
+			TableName: aws.String("movies"),
+			AttributeDefinitions: []*dynamodb.AttributeDefinition{
+				{AttributeName: aws.String("director"), AttributeType: aws.String("S")},
+				{AttributeName: aws.String("year"), AttributeType: aws.String("N")},
+			},
+			GlobalSecondaryIndexUpdates: []*dynamodb.GlobalSecondaryIndexUpdate{{
+				Create: &dynamodb.CreateGlobalSecondaryIndexAction{
+					IndexName: aws.String("director_year"),
+					KeySchema: []*dynamodb.KeySchemaElement{
+						{AttributeName: aws.String("director"), KeyType: aws.String("HASH")},
+						{AttributeName: aws.String("year"), KeyType: aws.String("RANGE")},
+					},
+					Projection: &dynamodb.Projection{ProjectionType: aws.String("KEYS_ONLY")},
+					ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+						ReadCapacityUnits:  aws.Int64(1),
+						WriteCapacityUnits: aws.Int64(2),
+					},
+				},
+			}},
+		}}, dynamo.reqs)
+	})
+
+	t.Run("throughput", func(t *testing.T) {
+		prepared, err := prepareAlter(`ALTER TABLE movies SET PROVISIONED THROUGHPUT READ 5 WRITE 5, ALTER INDEX year_title SET PROVISIONED THROUGHPUT READ 2 WRITE 3`)
+		require.NoError(t, err)
+		dynamo := &fakeUpdateTable{}
+		_, err = prepared.Do(context.Background(), dynamo, nil)
+		require.NoError(t, err)
+		require.Equal(t, 0, dynamo.describes)
+		require.Equal(t, []*dynamodb.UpdateTableInput{{
+			TableName: aws.String("movies"),
+			ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(5),
+				WriteCapacityUnits: aws.Int64(5),
+			},
+			GlobalSecondaryIndexUpdates: []*dynamodb.GlobalSecondaryIndexUpdate{{
+				Update: &dynamodb.UpdateGlobalSecondaryIndexAction{
+					IndexName: aws.String("year_title"),
+					ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+						ReadCapacityUnits:  aws.Int64(2),
+						WriteCapacityUnits: aws.Int64(3),
+					},
+				},
+			}},
+		}}, dynamo.reqs)
+	})
+
+	t.Run("create index", func(t *testing.T) {
+		ast, err := parser.Parse(`CREATE GLOBAL INDEX director_title ON movies HASH(director STRING) RANGE(title) PROJECTION INCLUDE (rating)`)
+		require.NoError(t, err)
+		prepared, err := PrepareCreateIndex(schema.NewTableLoader(nil), ast.CreateIndex)
+		require.NoError(t, err)
+		dynamo := &fakeUpdateTable{}
+		_, err = prepared.Do(context.Background(), dynamo, nil)
+		require.NoError(t, err)
+		require.Equal(t, []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("director"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("title"), AttributeType: aws.String("S")},
+		}, dynamo.reqs[0].AttributeDefinitions)
+		require.Equal(t, &dynamodb.CreateGlobalSecondaryIndexAction{
+			IndexName: aws.String("director_title"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("director"), KeyType: aws.String("HASH")},
+				{AttributeName: aws.String("title"), KeyType: aws.String("RANGE")},
+			},
+			Projection: &dynamodb.Projection{
+				ProjectionType:   aws.String("INCLUDE"),
+				NonKeyAttributes: aws.StringSlice([]string{"rating"}),
+			},
+		}, dynamo.reqs[0].GlobalSecondaryIndexUpdates[0].Create)
+
+		ast, err = parser.Parse(`DROP INDEX director_title ON movies`)
+		require.NoError(t, err)
+		prepared, err = PrepareDropIndex(schema.NewTableLoader(nil), ast.DropIndex)
+		require.NoError(t, err)
+		require.Equal(t, []*dynamodb.GlobalSecondaryIndexUpdate{{
+			Delete: &dynamodb.DeleteGlobalSecondaryIndexAction{IndexName: aws.String("director_title")},
+		}}, prepared.Update.GlobalSecondaryIndexUpdates)
+	})
+
+	t.Run("reloads schema after waiting", func(t *testing.T) {
+		ast, err := parser.Parse(`ALTER TABLE movies DROP INDEX director_title WAIT`)
+		require.NoError(t, err)
+		dynamo := &queriedUpdateTable{}
+		dynamo.tables = schema.NewTableLoader(dynamo)
+		prepared, err := PrepareAlterTable(dynamo.tables, ast.AlterTable)
+		require.NoError(t, err)
+		_, err = prepared.Do(context.Background(), dynamo, nil)
+		require.NoError(t, err)
+		describes := dynamo.describes
+		_, err = dynamo.tables.Get(context.Background(), "movies")
+		require.NoError(t, err)
+		require.Equal(t, describes+1, dynamo.describes)
+	})
+
+	t.Run("unknown attribute type", func(t *testing.T) {
+		prepared, err := prepareAlter(`ALTER TABLE movies ADD GLOBAL INDEX director_index HASH(director) PROJECTION ALL PROVISIONED THROUGHPUT READ 1 WRITE 1`)
+		require.NoError(t, err)
+		_, err = prepared.Do(context.Background(), &fakeUpdateTable{}, nil)
+		require.EqualError(t, err, `type of attribute "director" is unknown, since the table does not define it yet, declare it with a type such as director STRING`)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := prepareAlter(`ALTER TABLE movies DROP INDEX a, DROP INDEX b`)
+		require.EqualError(t, err, "ALTER TABLE may only add or drop one index at a time")
+		_, err = prepareAlter(`ALTER TABLE movies ADD director STRING`)
+		require.EqualError(t, err, `attribute "director" must be a key of the index being added`)
+	})
+}
@@ -0,0 +1,82 @@
+package querybuilder
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// maxBatchAttempts is the number of times a batch request is attempted before giving up on its unprocessed items.
+const maxBatchAttempts = 10
+
+var (
+	// The delay before the first retry of a batch request, doubled on each following retry.
+	batchRetryDelay = 50 * time.Millisecond
+	// The maximum delay between retries of a batch request.
+	maxBatchRetryDelay = 5 * time.Second
+)
+
+// BatchGet gets all items in the request, retrying unprocessed keys with exponential backoff.
+func BatchGet(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, req *dynamodb.BatchGetItemInput) ([]map[string]*dynamodb.AttributeValue, error) {
+	var items []map[string]*dynamodb.AttributeValue
+	for attempt := 1; ; attempt++ {
+		resp, err := dynamo.BatchGetItemWithContext(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, tableItems := range resp.Responses {
+			items = append(items, tableItems...)
+		}
+		if len(resp.UnprocessedKeys) == 0 {
+			return items, nil
+		}
+		if attempt == maxBatchAttempts {
+			return nil, fmt.Errorf("BatchGetItem: keys still unprocessed after %d attempts", maxBatchAttempts)
+		}
+		if err := backoff(ctx, attempt); err != nil {
+			return nil, err
+		}
+		req = &dynamodb.BatchGetItemInput{RequestItems: resp.UnprocessedKeys}
+	}
+}
+
+// BatchWrite makes all writes in the request, retrying unprocessed items with exponential backoff.
+func BatchWrite(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, req *dynamodb.BatchWriteItemInput) error {
+	for attempt := 1; ; attempt++ {
+		resp, err := dynamo.BatchWriteItemWithContext(ctx, req)
+		if err != nil {
+			return err
+		}
+		if len(resp.UnprocessedItems) == 0 {
+			return nil
+		}
+		if attempt == maxBatchAttempts {
+			return fmt.Errorf("BatchWriteItem: items still unprocessed after %d attempts", maxBatchAttempts)
+		}
+		if err := backoff(ctx, attempt); err != nil {
+			return err
+		}
+		req = &dynamodb.BatchWriteItemInput{RequestItems: resp.UnprocessedItems}
+	}
+}
+
+// backoff waits before the retry following the given attempt, using exponential backoff with jitter.
+func backoff(ctx context.Context, attempt int) error {
+	delay := batchRetryDelay << uint(attempt-1)
+	if delay > maxBatchRetryDelay || delay <= 0 {
+		delay = maxBatchRetryDelay
+	}
+	delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
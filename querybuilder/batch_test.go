@@ -0,0 +1,142 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/schema"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+// unprocessedBatchGet processes one key of each BatchGetItem request, and returns the rest as unprocessed.
+type unprocessedBatchGet struct {
+	dynamodbiface.DynamoDBAPI
+	calls int
+}
+
+func (d *unprocessedBatchGet) BatchGetItemWithContext(ctx aws.Context, req *dynamodb.BatchGetItemInput, opts ...request.Option) (*dynamodb.BatchGetItemOutput, error) {
+	d.calls++
+	resp := &dynamodb.BatchGetItemOutput{
+		Responses:       make(map[string][]map[string]*dynamodb.AttributeValue),
+		UnprocessedKeys: make(map[string]*dynamodb.KeysAndAttributes),
+	}
+	for table, keys := range req.RequestItems {
+		resp.Responses[table] = keys.Keys[:1]
+		if len(keys.Keys) > 1 {
+			unprocessed := *keys
+			unprocessed.Keys = keys.Keys[1:]
+			resp.UnprocessedKeys[table] = &unprocessed
+		}
+	}
+	return resp, nil
+}
+
+func TestBatchGet(t *testing.T) {
+	defer func(delay time.Duration) { batchRetryDelay = delay }(batchRetryDelay)
+	batchRetryDelay = time.Millisecond
+
+	newRequest := func(n int) *dynamodb.BatchGetItemInput {
+		keys := make([]map[string]*dynamodb.AttributeValue, n)
+		for i := range keys {
+			keys[i] = map[string]*dynamodb.AttributeValue{"id": {N: aws.String(strconv.Itoa(i))}}
+		}
+		return &dynamodb.BatchGetItemInput{
+			RequestItems: map[string]*dynamodb.KeysAndAttributes{"users": {Keys: keys}},
+		}
+	}
+
+	t.Run("retries unprocessed keys", func(t *testing.T) {
+		dynamo := &unprocessedBatchGet{}
+		items, err := BatchGet(context.Background(), dynamo, newRequest(3))
+		require.NoError(t, err)
+		require.Equal(t, newRequest(3).RequestItems["users"].Keys, items)
+		require.Equal(t, 3, dynamo.calls)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		dynamo := &unprocessedBatchGet{}
+		_, err := BatchGet(context.Background(), dynamo, newRequest(maxBatchAttempts+1))
+		require.EqualError(t, err, "BatchGetItem: keys still unprocessed after 10 attempts")
+		require.Equal(t, maxBatchAttempts, dynamo.calls)
+	})
+}
+
+// unprocessedBatchWrite processes the first n items of each BatchWriteItem request, and returns the rest as
+// unprocessed.
+type unprocessedBatchWrite struct {
+	dynamodbiface.DynamoDBAPI
+	n       int
+	mu      sync.Mutex
+	calls   int
+	written []map[string]*dynamodb.AttributeValue
+}
+
+func (d *unprocessedBatchWrite) BatchWriteItemWithContext(ctx aws.Context, req *dynamodb.BatchWriteItemInput, opts ...request.Option) (*dynamodb.BatchWriteItemOutput, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls++
+	resp := &dynamodb.BatchWriteItemOutput{UnprocessedItems: make(map[string][]*dynamodb.WriteRequest)}
+	for table, writes := range req.RequestItems {
+		if len(writes) > maxBatchWriteItems {
+			return nil, fmt.Errorf("too many items: %d", len(writes))
+		}
+		n := d.n
+		if n > len(writes) {
+			n = len(writes)
+		}
+		for _, write := range writes[:n] {
+			d.written = append(d.written, write.PutRequest.Item)
+		}
+		if len(writes) > n {
+			resp.UnprocessedItems[table] = writes[n:]
+		}
+	}
+	return resp, nil
+}
+
+func TestBatchWrite(t *testing.T) {
+	defer func(delay time.Duration) { batchRetryDelay = delay }(batchRetryDelay)
+	batchRetryDelay = time.Millisecond
+
+	t.Run("retries unprocessed items", func(t *testing.T) {
+		dynamo := &unprocessedBatchWrite{n: 1}
+		req := &dynamodb.BatchWriteItemInput{RequestItems: map[string][]*dynamodb.WriteRequest{}}
+		for i := 0; i < 3; i++ {
+			item := map[string]*dynamodb.AttributeValue{"id": {N: aws.String(strconv.Itoa(i))}}
+			req.RequestItems["users"] = append(req.RequestItems["users"], &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: item}})
+		}
+		require.NoError(t, BatchWrite(context.Background(), dynamo, req))
+		require.Len(t, dynamo.written, 3)
+		require.Equal(t, 3, dynamo.calls)
+	})
+
+	t.Run("replace not atomic", func(t *testing.T) {
+		insert := &PreparedInsert{Table: schema.NewTableFromCreate(fixtures.Movies.Create), Replace: true, NotAtomic: true}
+
+		var movies []fixtures.Movie
+		for i := 0; i < 60; i++ {
+			movies = append(movies, fixtures.Movie{Title: fmt.Sprintf("movie %d", i%50), Year: 2000, Info: fixtures.MovieInfo{Rating: float64(i)}})
+		}
+		dynamo := &unprocessedBatchWrite{n: 10}
+		result, err := insert.Do(context.Background(), dynamo, []driver.NamedValue{{Ordinal: 1, Value: movies}})
+		require.NoError(t, err)
+		require.Equal(t, &DriverResult{count: 50}, result)
+		require.Len(t, dynamo.written, 50)
+		for _, item := range dynamo.written {
+			if *item["title"].S == "movie 5" {
+				require.Equal(t, "55", *item["info"].M["rating"].N)
+			}
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/alecthomas/repr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// PreparedCheck checks that an item exists and matches a condition. In a transaction, it is a ConditionCheck that
+// fails the transaction if the condition does not hold. Outside a transaction, the item is read and the condition is
+// evaluated locally.
+type PreparedCheck struct {
+	Check            *dynamodb.ConditionCheck
+	Key              map[string]string
+	Condition        *parser.AndExpression
+	NamedParams      NamedParams
+	PositionalParams map[int]string
+	FixedParams      map[string]interface{}
+}
+
+func PrepareCheck(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) (*PreparedCheck, error) {
+	check := ast.Check
+	if check == nil {
+		return nil, fmt.Errorf("expected CHECK but got %s", repr.String(ast))
+	}
+	table, err := tables.Get(ctx, check.Table)
+	if err != nil {
+		return nil, err
+	}
+	return prepareCheck(table, check)
+}
+
+func prepareCheck(table *schema.Table, check *parser.Check) (*PreparedCheck, error) {
+	ctx := NewContext(table, "")
+	if err := prepareValuesAndPlaceholders(ctx, check); err != nil {
+		return nil, err
+	}
+	if len(ctx.PositionalParams) > 0 && len(ctx.NamedParams) > 0 {
+		return nil, errors.New("cannot mix positional params (?) with named params (:param)")
+	}
+	key, cond, err := extractPrimaryKey(ctx, check.Where)
+	if err != nil {
+		return nil, err
+	}
+	// Outside a transaction the condition is evaluated locally, so it is limited to what evaluate supports.
+	if err := validateCondition(cond); err != nil {
+		return nil, err
+	}
+	conditionExpr, err := buildConditionExpression(ctx, cond)
+	if err != nil {
+		return nil, err
+	}
+	conditionExpr = requireExists(ctx, conditionExpr)
+
+	req := &dynamodb.ConditionCheck{
+		TableName:                &check.Table,
+		ConditionExpression:      aws.String(conditionExpr),
+		ExpressionAttributeNames: ctx.ExpressionAttributeNames(),
+	}
+	return &PreparedCheck{
+		Check:            req,
+		Key:              key,
+		Condition:        cond,
+		NamedParams:      ctx.NamedParams,
+		PositionalParams: ctx.PositionalParams,
+		FixedParams:      ctx.FixedParams,
+	}, nil
+}
+
+func (p *PreparedCheck) NewRequest(args []driver.NamedValue) (*dynamodb.ConditionCheck, error) {
+	values, err := bindArgs(p.FixedParams, p.NamedParams, p.PositionalParams, args)
+	if err != nil {
+		return nil, err
+	}
+	req := *p.Check
+	req.Key = bindKey(p.Key, values)
+	req.ExpressionAttributeValues = usedValues(values, req.ConditionExpression)
+	return &req, nil
+}
+
+// Do reads the item with a consistent read, and evaluates the condition against it. One row is affected if the
+// item exists and matches the condition, otherwise none are.
+func (p *PreparedCheck) Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
+	values, err := bindArgs(p.FixedParams, p.NamedParams, p.PositionalParams, args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := dynamo.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName:      p.Check.TableName,
+		Key:            bindKey(p.Key, values),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Item == nil {
+		return &DriverResult{count: 0}, nil
+	}
+	ok, err := evaluate(p.Condition, resp.Item, values)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &DriverResult{count: 0}, nil
+	}
+	return &DriverResult{count: 1}, nil
+}
+
+// Transact adds a ConditionCheck to the transaction, which fails the transaction on commit if the condition does
+// not hold.
+func (p *PreparedCheck) Transact(tx *Transaction, args []driver.NamedValue) (*DriverResult, error) {
+	req, err := p.NewRequest(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.add(*req.TableName, req.Key, &dynamodb.TransactWriteItem{ConditionCheck: req}); err != nil {
+		return nil, err
+	}
+	return &DriverResult{count: 1}, nil
+}
@@ -0,0 +1,176 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+// fakeGetItem returns a fixed item from GetItem.
+type fakeGetItem struct {
+	dynamodbiface.DynamoDBAPI
+	item map[string]*dynamodb.AttributeValue
+	reqs []*dynamodb.GetItemInput
+}
+
+func (d *fakeGetItem) GetItemWithContext(ctx aws.Context, req *dynamodb.GetItemInput, opts ...request.Option) (*dynamodb.GetItemOutput, error) {
+	d.reqs = append(d.reqs, req)
+	return &dynamodb.GetItemOutput{Item: d.item}, nil
+}
+
+func TestPrepareCheck(t *testing.T) {
+	gameScores := schema.NewTableFromCreate(fixtures.GameScores.Create)
+	ast, err := parser.Parse(`CHECK gamescores WHERE UserId = ? AND GameTitle = ? AND TopScore >= ?`)
+	require.NoError(t, err)
+	prepared, err := prepareCheck(gameScores, ast.Check)
+	require.NoError(t, err)
+	args := []driver.NamedValue{
+		{Ordinal: 1, Value: "101"},
+		{Ordinal: 2, Value: "Galaxy Invaders"},
+		{Ordinal: 3, Value: int64(1000)},
+	}
+	key := map[string]*dynamodb.AttributeValue{
+		"UserId":    {S: aws.String("101")},
+		"GameTitle": {S: aws.String("Galaxy Invaders")},
+	}
+
+	t.Run("transact", func(t *testing.T) {
+		tx, err := NewTransaction()
+		require.NoError(t, err)
+		result, err := prepared.Transact(tx, args)
+		require.NoError(t, err)
+		require.Equal(t, &DriverResult{count: 1}, result)
+		require.Equal(t, []*dynamodb.TransactWriteItem{{
+			ConditionCheck: &dynamodb.ConditionCheck{
+				TableName:           aws.String("gamescores"),
+				ConditionExpression: aws.String("attribute_exists(UserId) AND TopScore >= :_pos3"),
+				Key:                 key,
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":_pos3": {N: aws.String("1000")},
+				},
+			},
+		}}, tx.items)
+	})
+
+	t.Run("do", func(t *testing.T) {
+		dynamo := &fakeGetItem{item: map[string]*dynamodb.AttributeValue{
+			"UserId":    {S: aws.String("101")},
+			"GameTitle": {S: aws.String("Galaxy Invaders")},
+			"TopScore":  {N: aws.String("5842")},
+		}}
+		result, err := prepared.Do(context.Background(), dynamo, args)
+		require.NoError(t, err)
+		require.Equal(t, &DriverResult{count: 1}, result)
+		require.Equal(t, []*dynamodb.GetItemInput{{
+			TableName:      aws.String("gamescores"),
+			Key:            key,
+			ConsistentRead: aws.Bool(true),
+		}}, dynamo.reqs)
+
+		dynamo.item["TopScore"] = &dynamodb.AttributeValue{N: aws.String("24")}
+		result, err = prepared.Do(context.Background(), dynamo, args)
+		require.NoError(t, err)
+		require.Equal(t, &DriverResult{count: 0}, result)
+
+		dynamo.item = nil
+		result, err = prepared.Do(context.Background(), dynamo, args)
+		require.NoError(t, err)
+		require.Equal(t, &DriverResult{count: 0}, result)
+	})
+
+	t.Run("requires primary key", func(t *testing.T) {
+		ast, err := parser.Parse(`CHECK gamescores WHERE UserId = ? AND TopScore >= ?`)
+		require.NoError(t, err)
+		_, err = prepareCheck(gameScores, ast.Check)
+		require.EqualError(t, err, "partition key and sort key must appear in the WHERE clause in equality conditions, such as: WHERE UserId = :param1 AND GameTitle = :param2")
+	})
+
+	t.Run("rejects functions that cannot be evaluated", func(t *testing.T) {
+		ast, err := parser.Parse(`CHECK gamescores WHERE UserId = ? AND GameTitle = ? AND (size(Wins) OR attribute_exists(Losses))`)
+		require.NoError(t, err)
+		_, err = prepareCheck(gameScores, ast.Check)
+		require.EqualError(t, err, "unsupported function size in condition")
+	})
+}
+
+func TestEvaluate(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"title":  {S: aws.String("Big")},
+		"year":   {N: aws.String("1988")},
+		"rating": {N: aws.String("7.3")},
+		"tags":   {SS: aws.StringSlice([]string{"comedy", "fantasy"})},
+		"info": {M: map[string]*dynamodb.AttributeValue{
+			"actors": {L: []*dynamodb.AttributeValue{{S: aws.String("Tom Hanks")}}},
+		}},
+		"old_tags": {SS: aws.StringSlice([]string{"fantasy", "comedy"})},
+		"old_info": {M: map[string]*dynamodb.AttributeValue{
+			"actors": {L: []*dynamodb.AttributeValue{{S: aws.String("Tom Hanks")}}},
+		}},
+		"scores":     {NS: aws.StringSlice([]string{"1", "2.5"})},
+		"old_scores": {NS: aws.StringSlice([]string{"2.50", "1.0"})},
+		"cast":       {L: []*dynamodb.AttributeValue{{S: aws.String("Tom Hanks")}, {S: aws.String("Elizabeth Perkins")}}},
+		"seen":       {BOOL: aws.Bool(true)},
+		"sequel":     {NULL: aws.Bool(true)},
+	}
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		{`year = 1988`, true},
+		{`year = 1988.0`, true},
+		{`year <> 1988`, false},
+		{`year <> "1988"`, true},
+		{`rating > 7`, true},
+		{`rating BETWEEN 7.5 AND 8`, false},
+		{`title < "Heat"`, true},
+		{`title IN ("Heat", "Big")`, true},
+		{`missing = 1`, false},
+		{`missing <> 1`, true},
+		{`attribute_exists(info.actors[0])`, true},
+		{`attribute_not_exists(info.actors[1])`, true},
+		{`attribute_type(tags, "SS")`, true},
+		{`begins_with(info.actors[0], "Tom")`, true},
+		{`contains(tags, "comedy")`, true},
+		{`contains(title, "ig")`, true},
+		{`NOT contains(tags, "drama")`, true},
+		{`(year = 1 OR year = 1988) AND title = "Big"`, true},
+		{`year = 1988 AND title = "Heat"`, false},
+		{`tags = old_tags`, true},
+		{`scores = old_scores`, true},
+		{`info = old_info`, true},
+		{`info <> old_info`, false},
+		{`info.actors = cast`, false},
+		{`info.actors <> cast`, true},
+		{`tags = scores`, false},
+		{`info.actors < cast`, false},
+		{`seen = true`, true},
+		{`seen <> false`, true},
+		{`seen > false`, false},
+		{`seen >= true`, false},
+		{`sequel = null`, true},
+		{`sequel <= null`, false},
+	}
+	for _, test := range tests {
+		t.Run(test.cond, func(t *testing.T) {
+			ast, err := parser.Parse(`CHECK movies WHERE ` + test.cond)
+			require.NoError(t, err)
+			ctx := NewContext(schema.NewTableFromCreate(fixtures.Movies.Create), "")
+			require.NoError(t, prepareValuesAndPlaceholders(ctx, ast.Check))
+			values, err := bindArgs(ctx.FixedParams, nil, nil, nil)
+			require.NoError(t, err)
+			got, err := evaluate(ast.Check.Where, item, values)
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
@@ -4,6 +4,8 @@ package querybuilder
 import (
 	"context"
 	"database/sql/driver"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/alecthomas/repr"
@@ -12,65 +14,167 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
 	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
 )
 
-func PrepareCreateTable(ast *parser.AST) (ExecStmt, error) {
+func PrepareCreateTable(tables *schema.TableLoader, ast *parser.AST) (ExecStmt, error) {
+	stmt := ast.CreateTable
+	req, ttl, tags, err := buildCreateTable(stmt)
+	if err != nil {
+		return nil, err
+	}
 	return execStatementFunc(func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
-		stmt := ast.CreateTable
-		req := &dynamodb.CreateTableInput{
-			TableName:             &stmt.Table,
-			ProvisionedThroughput: mapProvisionedThroughput(stmt.ProvisionedThroughput),
+		created, err := dynamo.CreateTableWithContext(ctx, req)
+		switch {
+		case stmt.IfNotExists && hasErrorCode(err, dynamodb.ErrCodeResourceInUseException):
+			// The table already exists, so its options are left as they are.
+		case err != nil:
+			return nil, err
+		default:
+			if err := setCreatedTableOptions(ctx, dynamo, created.TableDescription, ttl, tags); err != nil {
+				return nil, err
+			}
+		}
+		// A table of the same name may have been dropped and loaded before, with a different key schema.
+		tables.Invalidate(stmt.Table)
+		if stmt.Wait {
+			if err := waitForTable(ctx, dynamo, stmt.Table, false); err != nil {
+				return nil, err
+			}
+			// A query made while waiting may have loaded the schema before the indexes were active.
+			tables.Invalidate(stmt.Table)
+		}
+		return &DriverResult{count: 0}, nil
+	}), nil
+}
+
+// setCreatedTableOptions enables the time to live and adds the tags, which can only be done once the table has been
+// created.
+func setCreatedTableOptions(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, table *dynamodb.TableDescription, ttl *dynamodb.UpdateTimeToLiveInput, tags []*dynamodb.Tag) error {
+	if ttl == nil && len(tags) == 0 {
+		return nil
+	}
+	if err := waitForTable(ctx, dynamo, *table.TableName, false); err != nil {
+		return err
+	}
+	if ttl != nil {
+		if _, err := dynamo.UpdateTimeToLiveWithContext(ctx, ttl); err != nil {
+			return err
+		}
+	}
+	if len(tags) > 0 {
+		_, err := dynamo.TagResourceWithContext(ctx, &dynamodb.TagResourceInput{
+			ResourceArn: table.TableArn,
+			Tags:        tags,
+		})
+		if err != nil {
+			return err
 		}
-		for _, entry := range stmt.Entries {
-			switch {
-			case entry.Attr != nil:
-				attr := entry.Attr
-				typ := strings.ToUpper(attr.Type[0:1])
-				req.AttributeDefinitions = append(req.AttributeDefinitions, &dynamodb.AttributeDefinition{
+	}
+	return nil
+}
+
+// buildCreateTable builds the CreateTable request, the UpdateTimeToLive request to make after it if the TTL option is
+// set, and the tags to add to the table with TagResource.
+func buildCreateTable(stmt *parser.CreateTable) (*dynamodb.CreateTableInput, *dynamodb.UpdateTimeToLiveInput, []*dynamodb.Tag, error) {
+	payPerRequest, err := validateBillingMode(stmt)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	req := &dynamodb.CreateTableInput{
+		TableName:             &stmt.Table,
+		ProvisionedThroughput: mapProvisionedThroughput(stmt.ProvisionedThroughput),
+	}
+	if stmt.BillingMode != nil {
+		req.BillingMode = aws.String(dynamodb.BillingModeProvisioned)
+		if payPerRequest {
+			req.BillingMode = aws.String(dynamodb.BillingModePayPerRequest)
+		}
+	}
+	for _, entry := range stmt.Entries {
+		switch {
+		case entry.Attr != nil:
+			attr := entry.Attr
+			req.AttributeDefinitions = append(req.AttributeDefinitions, mapAttributeDefinition(attr))
+			if attr.Key != "" {
+				req.KeySchema = append(req.KeySchema, &dynamodb.KeySchemaElement{
 					AttributeName: &attr.Name,
-					AttributeType: &typ,
-				})
-				if attr.Key != "" {
-					req.KeySchema = append(req.KeySchema, &dynamodb.KeySchemaElement{
-						AttributeName: &attr.Name,
-						KeyType:       aws.String(strings.ToUpper(attr.Key)),
-					})
-				}
-
-			case entry.GlobalSecondaryIndex != nil:
-				gsi := entry.GlobalSecondaryIndex
-				reqgsi := &dynamodb.GlobalSecondaryIndex{
-					IndexName: &gsi.Name,
-					KeySchema: []*dynamodb.KeySchemaElement{
-						{AttributeName: &gsi.PartitionKey, KeyType: aws.String("HASH")},
-					},
-					Projection:            mapProjection(gsi.Projection),
-					ProvisionedThroughput: mapProvisionedThroughput(gsi.ProvisionedThroughput),
-				}
-				if gsi.SortKey != "" {
-					reqgsi.KeySchema = append(reqgsi.KeySchema,
-						&dynamodb.KeySchemaElement{AttributeName: &gsi.SortKey, KeyType: aws.String("RANGE")},
-					)
-				}
-				req.GlobalSecondaryIndexes = append(req.GlobalSecondaryIndexes, reqgsi)
-
-			case entry.LocalSecondaryIndex != nil:
-				lsi := entry.LocalSecondaryIndex
-				req.LocalSecondaryIndexes = append(req.LocalSecondaryIndexes, &dynamodb.LocalSecondaryIndex{
-					IndexName: &lsi.Name,
-					KeySchema: []*dynamodb.KeySchemaElement{
-						{AttributeName: &lsi.SortKey, KeyType: aws.String("RANGE")},
-					},
-					Projection: mapProjection(lsi.Projection),
+					KeyType:       aws.String(strings.ToUpper(attr.Key)),
 				})
-
-			default:
-				panic(repr.String(entry))
 			}
+
+		case entry.GlobalSecondaryIndex != nil:
+			req.GlobalSecondaryIndexes = append(req.GlobalSecondaryIndexes, mapGlobalSecondaryIndex(entry.GlobalSecondaryIndex))
+
+		case entry.LocalSecondaryIndex != nil:
+			lsi := entry.LocalSecondaryIndex
+			req.LocalSecondaryIndexes = append(req.LocalSecondaryIndexes, &dynamodb.LocalSecondaryIndex{
+				IndexName: &lsi.Name,
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: &lsi.SortKey, KeyType: aws.String("RANGE")},
+				},
+				Projection: mapProjection(lsi.Projection),
+			})
+
+		default:
+			panic(repr.String(entry))
 		}
-		_, err := dynamo.CreateTableWithContext(ctx, req)
-		return &DriverResult{count: 0}, err
-	}), nil
+	}
+	ttl, tags, err := applyTableOptions(req, stmt.Options)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return req, ttl, tags, nil
+}
+
+// validateBillingMode checks that throughput is set on the table and its global indexes if and only if the table is
+// provisioned, which is the default. It returns whether the table is billed per request.
+func validateBillingMode(stmt *parser.CreateTable) (payPerRequest bool, err error) {
+	payPerRequest = stmt.BillingMode != nil && strings.EqualFold(*stmt.BillingMode, dynamodb.BillingModePayPerRequest)
+	if payPerRequest && stmt.ProvisionedThroughput != nil {
+		return false, errors.New("PROVISIONED THROUGHPUT cannot be set with BILLING MODE PAY_PER_REQUEST")
+	}
+	if !payPerRequest && stmt.ProvisionedThroughput == nil {
+		return false, errors.New("PROVISIONED THROUGHPUT is required unless BILLING MODE is PAY_PER_REQUEST")
+	}
+	for _, entry := range stmt.Entries {
+		gsi := entry.GlobalSecondaryIndex
+		if gsi == nil {
+			continue
+		}
+		if payPerRequest && gsi.ProvisionedThroughput != nil {
+			return false, fmt.Errorf("index %q cannot set PROVISIONED THROUGHPUT with BILLING MODE PAY_PER_REQUEST", gsi.Name)
+		}
+		if !payPerRequest && gsi.ProvisionedThroughput == nil {
+			return false, fmt.Errorf("index %q requires PROVISIONED THROUGHPUT unless BILLING MODE is PAY_PER_REQUEST", gsi.Name)
+		}
+	}
+	return payPerRequest, nil
+}
+
+func mapAttributeDefinition(attr *parser.TableAttr) *dynamodb.AttributeDefinition {
+	typ := strings.ToUpper(attr.Type[0:1])
+	return &dynamodb.AttributeDefinition{
+		AttributeName: &attr.Name,
+		AttributeType: &typ,
+	}
+}
+
+func mapGlobalSecondaryIndex(gsi *parser.GlobalSecondaryIndex) *dynamodb.GlobalSecondaryIndex {
+	out := &dynamodb.GlobalSecondaryIndex{
+		IndexName: &gsi.Name,
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: &gsi.PartitionKey, KeyType: aws.String("HASH")},
+		},
+		Projection:            mapProjection(gsi.Projection),
+		ProvisionedThroughput: mapProvisionedThroughput(gsi.ProvisionedThroughput),
+	}
+	if gsi.SortKey != "" {
+		out.KeySchema = append(out.KeySchema,
+			&dynamodb.KeySchemaElement{AttributeName: &gsi.SortKey, KeyType: aws.String("RANGE")},
+		)
+	}
+	return out
 }
 
 func mapProjection(projection *parser.Projection) *dynamodb.Projection {
@@ -93,6 +197,9 @@ func mapProjection(projection *parser.Projection) *dynamodb.Projection {
 }
 
 func mapProvisionedThroughput(throughput *parser.ProvisionedThroughput) *dynamodb.ProvisionedThroughput {
+	if throughput == nil {
+		return nil
+	}
 	return &dynamodb.ProvisionedThroughput{
 		ReadCapacityUnits:  &throughput.ReadCapacityUnits,
 		WriteCapacityUnits: &throughput.WriteCapacityUnits,
@@ -0,0 +1,125 @@
+package querybuilder
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+func TestBuildCreateTable(t *testing.T) {
+	build := func(query string) (*dynamodb.CreateTableInput, error) {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		req, _, _, err := buildCreateTable(ast.CreateTable)
+		return req, err
+	}
+
+	t.Run("pay per request", func(t *testing.T) {
+		req, err := build(`CREATE TABLE users (id STRING HASH KEY, email STRING, GLOBAL INDEX email_index HASH(email) PROJECTION KEYS_ONLY) BILLING MODE pay_per_request`)
+		require.NoError(t, err)
+		require.Equal(t, &dynamodb.CreateTableInput{
+			TableName:   aws.String("users"),
+			BillingMode: aws.String("PAY_PER_REQUEST"),
+			AttributeDefinitions: []*dynamodb.AttributeDefinition{
+				{AttributeName: aws.String("id"), AttributeType: aws.String("S")},
+				{AttributeName: aws.String("email"), AttributeType: aws.String("S")},
+			},
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("id"), KeyType: aws.String("HASH")},
+			},
+			GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{{
+				IndexName: aws.String("email_index"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("email"), KeyType: aws.String("HASH")},
+				},
+				Projection: &dynamodb.Projection{ProjectionType: aws.String("KEYS_ONLY")},
+			}},
+		}, req)
+	})
+
+	t.Run("provisioned", func(t *testing.T) {
+		req, err := build(`CREATE TABLE users (id STRING HASH KEY) BILLING MODE PROVISIONED PROVISIONED THROUGHPUT READ 1 WRITE 2`)
+		require.NoError(t, err)
+		require.Equal(t, aws.String("PROVISIONED"), req.BillingMode)
+		require.Equal(t, &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(1),
+			WriteCapacityUnits: aws.Int64(2),
+		}, req.ProvisionedThroughput)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		ast, err := parser.Parse(`CREATE TABLE events (id STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WITH (` +
+			`STREAM = new_and_old_images, TTL = expires_at, SSE = KMS, SSE_KMS_KEY = 'alias/events', ` +
+			`TABLE_CLASS = STANDARD_INFREQUENT_ACCESS, DELETION_PROTECTION = TRUE, TAGS = {team: 'payments'})`)
+		require.NoError(t, err)
+		req, ttl, tags, err := buildCreateTable(ast.CreateTable)
+		require.NoError(t, err)
+		require.Equal(t, &dynamodb.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: aws.String("NEW_AND_OLD_IMAGES"),
+		}, req.StreamSpecification)
+		require.Equal(t, &dynamodb.SSESpecification{
+			Enabled:        aws.Bool(true),
+			SSEType:        aws.String("KMS"),
+			KMSMasterKeyId: aws.String("alias/events"),
+		}, req.SSESpecification)
+		require.Equal(t, aws.String("STANDARD_INFREQUENT_ACCESS"), req.TableClass)
+		require.Equal(t, aws.Bool(true), req.DeletionProtectionEnabled)
+		require.Equal(t, []*dynamodb.Tag{{Key: aws.String("team"), Value: aws.String("payments")}}, tags)
+		require.Equal(t, &dynamodb.UpdateTimeToLiveInput{
+			TableName: aws.String("events"),
+			TimeToLiveSpecification: &dynamodb.TimeToLiveSpecification{
+				AttributeName: aws.String("expires_at"),
+				Enabled:       aws.Bool(true),
+			},
+		}, ttl)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			query string
+			err   string
+		}{
+			{
+				`CREATE TABLE users (id STRING HASH KEY)`,
+				"PROVISIONED THROUGHPUT is required unless BILLING MODE is PAY_PER_REQUEST",
+			},
+			{
+				`CREATE TABLE users (id STRING HASH KEY) BILLING MODE PAY_PER_REQUEST PROVISIONED THROUGHPUT READ 1 WRITE 1`,
+				"PROVISIONED THROUGHPUT cannot be set with BILLING MODE PAY_PER_REQUEST",
+			},
+			{
+				`CREATE TABLE users (id STRING HASH KEY, email STRING, GLOBAL INDEX email_index HASH(email) PROJECTION ALL PROVISIONED THROUGHPUT READ 1 WRITE 1) BILLING MODE PAY_PER_REQUEST`,
+				`index "email_index" cannot set PROVISIONED THROUGHPUT with BILLING MODE PAY_PER_REQUEST`,
+			},
+			{
+				`CREATE TABLE users (id STRING HASH KEY, email STRING, GLOBAL INDEX email_index HASH(email) PROJECTION ALL) PROVISIONED THROUGHPUT READ 1 WRITE 1`,
+				`index "email_index" requires PROVISIONED THROUGHPUT unless BILLING MODE is PAY_PER_REQUEST`,
+			},
+			{
+				`CREATE TABLE users (id STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WITH (STREAM = ALL)`,
+				"option STREAM must be one of KEYS_ONLY, NEW_AND_OLD_IMAGES, NEW_IMAGE, NONE, OLD_IMAGE, but was ALL",
+			},
+			{
+				`CREATE TABLE users (id STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WITH (TTL = a, TTL = b)`,
+				"option TTL is set more than once",
+			},
+			{
+				`CREATE TABLE users (id STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WITH (SSE_KMS_KEY = 'alias/users')`,
+				"option SSE_KMS_KEY requires SSE = KMS",
+			},
+			{
+				`CREATE TABLE users (id STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WITH (COLOR = blue)`,
+				"unknown table option COLOR, expected one of STREAM, TTL, SSE, SSE_KMS_KEY, TABLE_CLASS, DELETION_PROTECTION or TAGS",
+			},
+		}
+		for _, test := range tests {
+			_, err := build(test.query)
+			require.EqualError(t, err, test.err, test.query)
+		}
+	})
+}
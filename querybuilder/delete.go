@@ -0,0 +1,122 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/alecthomas/repr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+type PreparedDelete struct {
+	Delete           *dynamodb.DeleteItemInput
+	Key              map[string]string
+	NamedParams      NamedParams
+	PositionalParams map[int]string
+	FixedParams      map[string]interface{}
+}
+
+func PrepareDelete(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) (*PreparedDelete, error) {
+	del := ast.Delete
+	if del == nil {
+		return nil, fmt.Errorf("expected DELETE but got %s", repr.String(ast))
+	}
+	table, err := tables.Get(ctx, del.Table)
+	if err != nil {
+		return nil, err
+	}
+	return prepareDelete(table, del)
+}
+
+func prepareDelete(table *schema.Table, del *parser.Delete) (*PreparedDelete, error) {
+	ctx := NewContext(table, "")
+	if err := prepareValuesAndPlaceholders(ctx, del); err != nil {
+		return nil, err
+	}
+	if len(ctx.PositionalParams) > 0 && len(ctx.NamedParams) > 0 {
+		return nil, errors.New("cannot mix positional params (?) with named params (:param)")
+	}
+	key, cond, err := extractPrimaryKey(ctx, del.Where)
+	if err != nil {
+		return nil, err
+	}
+	conditionExpr, err := buildConditionExpression(ctx, cond)
+	if err != nil {
+		return nil, err
+	}
+	// Fail the condition on missing items so that they are not counted as deleted.
+	conditionExpr = requireExists(ctx, conditionExpr)
+
+	req := &dynamodb.DeleteItemInput{
+		TableName:                &del.Table,
+		ConditionExpression:      aws.String(conditionExpr),
+		ExpressionAttributeNames: ctx.ExpressionAttributeNames(),
+		ReturnValues:             del.Returning,
+	}
+	return &PreparedDelete{
+		Delete:           req,
+		Key:              key,
+		NamedParams:      ctx.NamedParams,
+		PositionalParams: ctx.PositionalParams,
+		FixedParams:      ctx.FixedParams,
+	}, nil
+}
+
+func (p *PreparedDelete) NewRequest(args []driver.NamedValue) (*dynamodb.DeleteItemInput, error) {
+	values, err := bindArgs(p.FixedParams, p.NamedParams, p.PositionalParams, args)
+	if err != nil {
+		return nil, err
+	}
+	req := *p.Delete
+	req.Key = bindKey(p.Key, values)
+	req.ExpressionAttributeValues = usedValues(values, req.ConditionExpression)
+	return &req, nil
+}
+
+func (p *PreparedDelete) Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
+	req, err := p.NewRequest(args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := dynamo.DeleteItemWithContext(ctx, req)
+	if isConditionalCheckFailed(err) {
+		// The item does not exist or did not match the WHERE clause.
+		return &DriverResult{count: 0}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &DriverResult{
+		count:    1,
+		returned: resp.Attributes,
+	}, nil
+}
+
+func (p *PreparedDelete) Transact(tx *Transaction, args []driver.NamedValue) (*DriverResult, error) {
+	req, err := p.NewRequest(args)
+	if err != nil {
+		return nil, err
+	}
+	if req.ReturnValues != nil && *req.ReturnValues != dynamodb.ReturnValueNone {
+		return nil, errReturningInTransaction
+	}
+	err = tx.add(*req.TableName, req.Key, &dynamodb.TransactWriteItem{
+		Delete: &dynamodb.Delete{
+			TableName:                 req.TableName,
+			Key:                       req.Key,
+			ConditionExpression:       req.ConditionExpression,
+			ExpressionAttributeNames:  req.ExpressionAttributeNames,
+			ExpressionAttributeValues: req.ExpressionAttributeValues,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &DriverResult{count: 1}, nil
+}
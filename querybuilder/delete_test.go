@@ -0,0 +1,71 @@
+package querybuilder
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+func TestPrepareDelete(t *testing.T) {
+	movies := schema.NewTableFromCreate(fixtures.Movies.Create)
+	gameScores := schema.NewTableFromCreate(fixtures.GameScores.Create)
+
+	t.Run("key only", func(t *testing.T) {
+		ast, err := parser.Parse(`DELETE FROM movies WHERE title = :title AND year = :year`)
+		require.NoError(t, err)
+		prepared, err := prepareDelete(movies, ast.Delete)
+		require.NoError(t, err)
+		req, err := prepared.NewRequest([]driver.NamedValue{
+			{Name: "title", Value: "Big"},
+			{Name: "year", Value: int64(1988)},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &dynamodb.DeleteItemInput{
+			TableName:           aws.String("movies"),
+			ConditionExpression: aws.String("attribute_exists(title)"),
+			Key: map[string]*dynamodb.AttributeValue{
+				"title": {S: aws.String("Big")},
+				"year":  {N: aws.String("1988")},
+			},
+		}, req)
+	})
+
+	t.Run("condition and returning", func(t *testing.T) {
+		ast, err := parser.Parse(`DELETE FROM gamescores WHERE GameTitle = ? AND UserId = ? AND TopScore < ? RETURNING ALL_OLD`)
+		require.NoError(t, err)
+		prepared, err := prepareDelete(gameScores, ast.Delete)
+		require.NoError(t, err)
+		req, err := prepared.NewRequest([]driver.NamedValue{
+			{Ordinal: 1, Value: "Galaxy Invaders"},
+			{Ordinal: 2, Value: "101"},
+			{Ordinal: 3, Value: int64(100)},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &dynamodb.DeleteItemInput{
+			TableName:           aws.String("gamescores"),
+			ConditionExpression: aws.String("attribute_exists(UserId) AND TopScore < :_pos3"),
+			Key: map[string]*dynamodb.AttributeValue{
+				"UserId":    {S: aws.String("101")},
+				"GameTitle": {S: aws.String("Galaxy Invaders")},
+			},
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":_pos3": {N: aws.String("100")},
+			},
+			ReturnValues: aws.String("ALL_OLD"),
+		}, req)
+	})
+
+	t.Run("full primary key required", func(t *testing.T) {
+		ast, err := parser.Parse(`DELETE FROM gamescores WHERE UserId = "101" AND TopScore < 100`)
+		require.NoError(t, err)
+		_, err = prepareDelete(gameScores, ast.Delete)
+		require.EqualError(t, err, `partition key and sort key must appear in the WHERE clause in equality conditions, such as: WHERE UserId = :param1 AND GameTitle = :param2`)
+	})
+}
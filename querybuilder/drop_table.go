@@ -9,13 +9,27 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 
 	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
 )
 
-func PrepareDropTable(drop *parser.DropTable) (ExecStmt, error) {
+func PrepareDropTable(tables *schema.TableLoader, drop *parser.DropTable) (ExecStmt, error) {
 	return execStatementFunc(func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
-		_, err := dynamo.DeleteTable(&dynamodb.DeleteTableInput{
+		_, err := dynamo.DeleteTableWithContext(ctx, &dynamodb.DeleteTableInput{
 			TableName: &drop.Table,
 		})
-		return &DriverResult{count: 0}, err
+		switch {
+		case drop.IfExists && hasErrorCode(err, dynamodb.ErrCodeResourceNotFoundException):
+		case err != nil:
+			return nil, err
+		}
+		tables.Invalidate(drop.Table)
+		if drop.Wait {
+			if err := waitForTable(ctx, dynamo, drop.Table, true); err != nil {
+				return nil, err
+			}
+			// A query made while waiting may have loaded the schema of the table being deleted.
+			tables.Invalidate(drop.Table)
+		}
+		return &DriverResult{count: 0}, nil
 	}), nil
 }
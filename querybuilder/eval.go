@@ -0,0 +1,350 @@
+package querybuilder
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// conditionFunctions maps the functions that evaluate supports to their number of arguments.
+var conditionFunctions = map[string]int{
+	"attribute_exists":     1,
+	"attribute_not_exists": 1,
+	"attribute_type":       2,
+	"begins_with":          2,
+	"contains":             2,
+}
+
+// validateCondition returns an error if the condition uses a function that evaluate does not support, such as size,
+// so that a condition is rejected when it is prepared rather than only when it is evaluated.
+func validateCondition(cond *parser.AndExpression) error {
+	return parser.Visit(cond, func(node parser.Node, next func() error) error {
+		if fn, ok := node.(*parser.FunctionExpression); ok {
+			if _, ok := conditionFunctions[fn.Function]; !ok {
+				return fmt.Errorf("unsupported function %s in condition", fn.Function)
+			}
+		}
+		return next()
+	})
+}
+
+// evaluate evaluates a condition against an item locally, with the same semantics as a DynamoDB condition expression.
+// Values must already have been replaced by placeholders, which are looked up in values. An empty condition holds.
+func evaluate(cond *parser.AndExpression, item map[string]*dynamodb.AttributeValue, values map[string]*dynamodb.AttributeValue) (bool, error) {
+	e := &evaluator{item: &dynamodb.AttributeValue{M: item}, values: values}
+	return e.and(cond)
+}
+
+type evaluator struct {
+	item   *dynamodb.AttributeValue
+	values map[string]*dynamodb.AttributeValue
+}
+
+func (e *evaluator) or(expr *parser.ConditionExpression) (bool, error) {
+	for _, and := range expr.Or {
+		ok, err := e.and(and)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+func (e *evaluator) and(expr *parser.AndExpression) (bool, error) {
+	for _, cond := range expr.And {
+		ok, err := e.condition(cond)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (e *evaluator) condition(cond *parser.Condition) (bool, error) {
+	switch {
+	case cond.TupleIn != nil:
+		return false, errTupleIn
+	case cond.Parenthesized != nil:
+		return e.or(cond.Parenthesized)
+	case cond.Not != nil:
+		ok, err := e.condition(cond.Not.Condition)
+		return !ok, err
+	case cond.Operand != nil:
+		return e.operand(cond.Operand)
+	case cond.Function != nil:
+		return e.function(cond.Function)
+	default:
+		return false, fmt.Errorf("unsupported condition")
+	}
+}
+
+func (e *evaluator) operand(cond *parser.ConditionOperand) (bool, error) {
+	lhs := resolvePath(e.item, cond.Operand)
+	rhs := cond.ConditionRHS
+	switch {
+	case rhs.Compare != nil:
+		return compare(rhs.Compare.Operator, lhs, e.resolve(rhs.Compare.Operand)), nil
+	case rhs.Between != nil:
+		return compare(">=", lhs, e.resolve(rhs.Between.Start)) && compare("<=", lhs, e.resolve(rhs.Between.End)), nil
+	case rhs.In != nil:
+		for _, value := range rhs.In.Values {
+			if compare("=", lhs, e.value(value)) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("unsupported condition on %s", cond.Operand)
+	}
+}
+
+func (e *evaluator) function(fn *parser.FunctionExpression) (bool, error) {
+	args := make([]*dynamodb.AttributeValue, len(fn.Args))
+	for i, arg := range fn.Args {
+		if arg.DocumentPath != nil {
+			args[i] = resolvePath(e.item, arg.DocumentPath)
+		} else {
+			args[i] = e.value(arg.Value)
+		}
+	}
+	n, ok := conditionFunctions[fn.Function]
+	if !ok {
+		return false, fmt.Errorf("unsupported function %s in condition", fn.Function)
+	}
+	if len(args) != n || !fn.FirstArgIsRef() {
+		return false, fmt.Errorf("%s expects a path and %d arguments", fn.Function, n-1)
+	}
+	switch fn.Function {
+	case "attribute_exists":
+		return args[0] != nil, nil
+	case "attribute_not_exists":
+		return args[0] == nil, nil
+	case "attribute_type":
+		if args[1] == nil || args[1].S == nil {
+			return false, fmt.Errorf("attribute_type expects a type name")
+		}
+		return args[0] != nil && attributeType(args[0]) == *args[1].S, nil
+	case "begins_with":
+		switch {
+		case args[0] == nil || args[1] == nil:
+			return false, nil
+		case args[0].S != nil && args[1].S != nil:
+			return strings.HasPrefix(*args[0].S, *args[1].S), nil
+		case args[0].B != nil && args[1].B != nil:
+			return bytes.HasPrefix(args[0].B, args[1].B), nil
+		}
+		return false, nil
+	default: // contains
+		return contains(args[0], args[1]), nil
+	}
+}
+
+func (e *evaluator) resolve(operand *parser.Operand) *dynamodb.AttributeValue {
+	if operand.SymbolRef != nil {
+		return resolvePath(e.item, operand.SymbolRef)
+	}
+	return e.value(operand.Value)
+}
+
+func (e *evaluator) value(value *parser.Value) *dynamodb.AttributeValue {
+	if value.PlaceHolder == nil {
+		return nil
+	}
+	return e.values[*value.PlaceHolder]
+}
+
+// resolvePath returns the attribute at path within the item, or nil if it does not exist.
+func resolvePath(pos *dynamodb.AttributeValue, path *parser.DocumentPath) *dynamodb.AttributeValue {
+	var ok bool
+	for _, frag := range path.Fragment {
+		if pos.M == nil {
+			return nil
+		}
+		pos, ok = pos.M[frag.Symbol]
+		if !ok {
+			return nil
+		}
+		for _, idx := range frag.Indexes {
+			if pos.L == nil || idx >= len(pos.L) {
+				return nil
+			}
+			pos = pos.L[idx]
+		}
+	}
+	return pos
+}
+
+// compare compares two values. As in DynamoDB, comparisons with a missing attribute or between different types are
+// false, except for <>. Any type may be compared for equality, but only numbers, strings and binary are ordered.
+func compare(op string, a, b *dynamodb.AttributeValue) bool {
+	switch op {
+	case "=":
+		return equalValues(a, b)
+	case "<>", "!=":
+		return !equalValues(a, b)
+	}
+	cmp, ok := compareValues(a, b)
+	switch op {
+	case "<":
+		return ok && cmp < 0
+	case "<=":
+		return ok && cmp <= 0
+	case ">":
+		return ok && cmp > 0
+	case ">=":
+		return ok && cmp >= 0
+	}
+	return false
+}
+
+func compareValues(a, b *dynamodb.AttributeValue) (int, bool) {
+	if a == nil || b == nil {
+		return 0, false
+	}
+	switch {
+	case a.N != nil && b.N != nil:
+		x, okx := new(big.Float).SetString(*a.N)
+		y, oky := new(big.Float).SetString(*b.N)
+		if !okx || !oky {
+			return 0, false
+		}
+		return x.Cmp(y), true
+	case a.S != nil && b.S != nil:
+		return strings.Compare(*a.S, *b.S), true
+	case a.B != nil && b.B != nil:
+		return bytes.Compare(a.B, b.B), true
+	}
+	return 0, false
+}
+
+// equalValues returns true if two values are equal. Lists are equal if their elements are equal in order, maps if
+// they have the same keys with equal values, and sets if they have the same elements in any order.
+func equalValues(a, b *dynamodb.AttributeValue) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	switch {
+	case a.L != nil && b.L != nil:
+		if len(a.L) != len(b.L) {
+			return false
+		}
+		for i := range a.L {
+			if !equalValues(a.L[i], b.L[i]) {
+				return false
+			}
+		}
+		return true
+	case a.M != nil && b.M != nil:
+		if len(a.M) != len(b.M) {
+			return false
+		}
+		for k, v := range a.M {
+			if !equalValues(v, b.M[k]) {
+				return false
+			}
+		}
+		return true
+	case a.SS != nil && b.SS != nil:
+		return equalSets(len(a.SS), len(b.SS), func(i, j int) bool { return *a.SS[i] == *b.SS[j] })
+	case a.NS != nil && b.NS != nil:
+		return equalSets(len(a.NS), len(b.NS), func(i, j int) bool {
+			return compare("=", &dynamodb.AttributeValue{N: a.NS[i]}, &dynamodb.AttributeValue{N: b.NS[j]})
+		})
+	case a.BS != nil && b.BS != nil:
+		return equalSets(len(a.BS), len(b.BS), func(i, j int) bool { return bytes.Equal(a.BS[i], b.BS[j]) })
+	case a.BOOL != nil && b.BOOL != nil:
+		return *a.BOOL == *b.BOOL
+	case a.NULL != nil && b.NULL != nil:
+		return true
+	}
+	cmp, ok := compareValues(a, b)
+	return ok && cmp == 0
+}
+
+// equalSets returns true if two sets of sizes n and m have the same elements, where equal(i, j) compares the ith
+// element of the first set to the jth of the second. Sets have no duplicates, so each element only needs a match.
+func equalSets(n, m int, equal func(i, j int) bool) bool {
+	if n != m {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		found := false
+		for j := 0; j < m && !found; j++ {
+			found = equal(i, j)
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// contains implements the contains function, which matches substrings of strings, and elements of sets and lists.
+func contains(a, b *dynamodb.AttributeValue) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	switch {
+	case a.S != nil && b.S != nil:
+		return strings.Contains(*a.S, *b.S)
+	case a.B != nil && b.B != nil:
+		return bytes.Contains(a.B, b.B)
+	case a.SS != nil:
+		for _, s := range a.SS {
+			if compare("=", &dynamodb.AttributeValue{S: s}, b) {
+				return true
+			}
+		}
+	case a.NS != nil:
+		for _, n := range a.NS {
+			if compare("=", &dynamodb.AttributeValue{N: n}, b) {
+				return true
+			}
+		}
+	case a.BS != nil:
+		for _, v := range a.BS {
+			if compare("=", &dynamodb.AttributeValue{B: v}, b) {
+				return true
+			}
+		}
+	case a.L != nil:
+		for _, v := range a.L {
+			if compare("=", v, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// attributeType returns the DynamoDB type descriptor of a value, as used by attribute_type.
+func attributeType(av *dynamodb.AttributeValue) string {
+	switch {
+	case av.S != nil:
+		return "S"
+	case av.N != nil:
+		return "N"
+	case av.B != nil:
+		return "B"
+	case av.BOOL != nil:
+		return "BOOL"
+	case av.NULL != nil:
+		return "NULL"
+	case av.SS != nil:
+		return "SS"
+	case av.NS != nil:
+		return "NS"
+	case av.BS != nil:
+		return "BS"
+	case av.L != nil:
+		return "L"
+	case av.M != nil:
+		return "M"
+	}
+	return ""
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql/driver"
 	"errors"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 )
@@ -37,3 +39,28 @@ func (i *DriverResult) RowsAffected() (int64, error) {
 func (i *DriverResult) Item() map[string]*dynamodb.AttributeValue {
 	return i.returned
 }
+
+// isConditionalCheckFailed returns true if the error is caused by a failed ConditionExpression.
+func isConditionalCheckFailed(err error) bool {
+	return hasErrorCode(err, dynamodb.ErrCodeConditionalCheckFailedException)
+}
+
+// isTransactionConditionFailed returns true if a transaction was canceled because a ConditionExpression failed.
+func isTransactionConditionFailed(err error) bool {
+	var canceled *dynamodb.TransactionCanceledException
+	if !errors.As(err, &canceled) {
+		return false
+	}
+	for _, reason := range canceled.CancellationReasons {
+		if aws.StringValue(reason.Code) == "ConditionalCheckFailed" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasErrorCode returns true if the error is an AWS error with the given code.
+func hasErrorCode(err error, code string) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == code
+}
@@ -0,0 +1,346 @@
+package querybuilder
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// explainMarker prefixes the names of placeholders that are bound in place of args by EXPLAIN. The marked values
+// are replaced with the names of the placeholders when the requests are formatted.
+const explainMarker = "\x00"
+
+var (
+	// matches a value bound by explainArgs in a request formatted as JSON
+	explainValueRegexp = regexp.MustCompile(`\{"S":"\\u0000([^"]*)"\}`)
+	// matches an item bound as a whole, after its value has been replaced
+	explainItemRegexp = regexp.MustCompile(`\{"\\u0000":"([^"]*)"\}`)
+)
+
+// explainedRequest is a request that a statement would make.
+type explainedRequest struct {
+	operation string
+	request   interface{}
+}
+
+// PrepareExplain prepares the explained statement, and returns a row for each DynamoDB request it would make. Each
+// row has the name of the operation, the request as JSON with args shown as their placeholders, and warnings about
+// how the statement uses DynamoDB.
+func PrepareExplain(ctx context.Context, tables *schema.TableLoader, explain *parser.Explain) (ShowStmt, error) {
+	stmt := explain.Statement
+	var (
+		reqs     []explainedRequest
+		warnings []string
+		err      error
+	)
+	switch {
+	case stmt.Select != nil:
+		reqs, warnings, err = explainSelect(ctx, tables, stmt.Select)
+	case stmt.Insert != nil:
+		reqs, warnings, err = explainInsert(ctx, tables, stmt)
+	case stmt.Update != nil:
+		reqs, err = explainUpdate(ctx, tables, stmt)
+	case stmt.Delete != nil:
+		reqs, err = explainDelete(ctx, tables, stmt)
+	case stmt.Check != nil:
+		reqs, warnings, err = explainCheck(ctx, tables, stmt)
+	default:
+		return nil, errors.New("EXPLAIN only supports SELECT, SCAN, INSERT, REPLACE, UPDATE, DELETE and CHECK")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ShowResult{Columns: []string{"operation", "request", "warnings"}}
+	var warning driver.Value
+	if len(warnings) > 0 {
+		warning = strings.Join(warnings, "\n")
+	}
+	for _, req := range reqs {
+		formatted, err := formatRequest(req.request)
+		if err != nil {
+			return nil, err
+		}
+		result.Rows = append(result.Rows, []driver.Value{req.operation, formatted, warning})
+	}
+	return showStatementFunc(func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) (*ShowResult, error) {
+		return result, nil
+	}), nil
+}
+
+func explainSelect(ctx context.Context, tables *schema.TableLoader, sel *parser.Select) ([]explainedRequest, []string, error) {
+	table, err := tables.Get(ctx, sel.From)
+	if err != nil {
+		return nil, nil, err
+	}
+	pq, err := prepare(table, sel)
+	if err != nil {
+		return nil, nil, err
+	}
+	args := explainArgs(pq.NamedParams, pq.PositionalParams)
+	var (
+		reqs     []explainedRequest
+		warnings []string
+		filtered bool
+	)
+	switch {
+	case pq.GetItem != nil:
+		req, err := pq.NewGetItemRequest(args)
+		if err != nil {
+			return nil, nil, err
+		}
+		reqs = append(reqs, explainedRequest{"GetItem", req})
+
+	case pq.BatchGet != nil:
+		batches, err := pq.NewBatchGetRequests(args)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, req := range batches {
+			reqs = append(reqs, explainedRequest{"BatchGetItem", req})
+		}
+
+	case pq.Scan != nil:
+		// Segments of a parallel scan only differ by their Segment, so a single request is shown.
+		req, err := pq.NewScanRequest(args)
+		if err != nil {
+			return nil, nil, err
+		}
+		reqs = append(reqs, explainedRequest{"Scan", req})
+		filtered = req.FilterExpression != nil
+		warnings = append(warnings, "SCAN reads the whole table or index, which consumes read capacity for every item")
+
+	default:
+		queries, err := pq.NewRequests(args)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, req := range queries {
+			reqs = append(reqs, explainedRequest{"Query", req})
+			filtered = filtered || req.FilterExpression != nil
+		}
+		if len(queries) > 1 {
+			warnings = append(warnings, fmt.Sprintf("the partition key is matched by %d values, so a Query is made for each", len(queries)))
+		}
+	}
+	if filtered {
+		warnings = append(warnings, "filter on non-key attributes will consume read capacity for skipped items")
+	}
+	if sel.Limit != nil && filtered {
+		// DynamoDB applies the Limit before the filter, so it cannot be used.
+		warnings = append(warnings, "LIMIT not pushed down because a filter is present, so pages are read until enough items match")
+	}
+	return reqs, warnings, nil
+}
+
+func explainInsert(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) ([]explainedRequest, []string, error) {
+	p, err := PrepareInsert(ctx, tables, ast)
+	if err != nil {
+		return nil, nil, err
+	}
+	var warnings []string
+	condValues, err := bindArgs(p.FixedParams, p.NamedParams, p.PositionalParams, explainArgs(p.NamedParams, p.PositionalParams))
+	if err != nil {
+		return nil, nil, err
+	}
+	condValues = usedValues(condValues, p.Condition, p.UpsertSet)
+	items := p.Values
+	// The items updated by ON CONFLICT DO UPDATE, if not the items themselves.
+	var updated []map[string]*dynamodb.AttributeValue
+	if len(p.Columns) > 0 || p.Templates != nil {
+		// The placeholders in the items stand in for the args bound to them.
+		items, condValues, err = p.items(explainArgs(p.NamedParams, p.PositionalParams))
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if len(items) == 0 {
+		// The item is only known once it is bound, so the placeholder stands in for it.
+		placeholder := "?1"
+		if p.Placeholder != "" {
+			placeholder = p.Placeholder
+		}
+		items = []map[string]*dynamodb.AttributeValue{{explainMarker: explainValue(placeholder)}}
+		if p.UpsertSet != nil {
+			// An update needs the key of the item, so the key attributes of the placeholder stand in for it.
+			updated = []map[string]*dynamodb.AttributeValue{{p.Table.HashKey: explainValue(placeholder + "." + p.Table.HashKey)}}
+			if p.Table.SortKey != "" {
+				updated[0][p.Table.SortKey] = explainValue(placeholder + "." + p.Table.SortKey)
+			}
+			warnings = append(warnings, "the attributes of the items bound to "+placeholder+" are set before the SET actions of ON CONFLICT")
+		}
+		if !p.NotAtomic {
+			warnings = append(warnings, "a list of items bound to "+placeholder+" is written with TransactWriteItems")
+		}
+	}
+	if (p.DoNothing || p.UpsertSet != nil) && (len(items) > 1 || len(p.Values) == 0) {
+		warnings = append(warnings, "a transaction canceled by existing items is retried without them")
+	}
+	if p.UpsertSet != nil {
+		// The items are put unless they exist, and only those that exist are then updated.
+		warnings = append(warnings, "items that exist are updated once the items that do not are put")
+		reqs := []explainedRequest{{"TransactWriteItems", p.toTransactWrite(items, nil)}}
+		if len(items) == 1 {
+			reqs = []explainedRequest{{"PutItem", p.toPutItem(items[0], nil)}}
+		}
+		if updated == nil {
+			updated = items
+		}
+		var writes []*dynamodb.TransactWriteItem
+		for _, item := range updated {
+			update, err := p.toUpdate(item, condValues)
+			if err != nil {
+				return nil, nil, err
+			}
+			writes = append(writes, &dynamodb.TransactWriteItem{Update: update})
+		}
+		if len(writes) == 1 {
+			return append(reqs, explainedRequest{"UpdateItem", toUpdateItem(writes[0].Update)}), warnings, nil
+		}
+		return append(reqs, explainedRequest{"TransactWriteItems", &dynamodb.TransactWriteItemsInput{TransactItems: writes}}), warnings, nil
+	}
+	if p.NotAtomic {
+		warnings = append(warnings, "NOT ATOMIC items are written with BatchWriteItem, so some may be written even if the statement fails")
+		var reqs []explainedRequest
+		for start := 0; start < len(items); start += maxBatchWriteItems {
+			end := start + maxBatchWriteItems
+			if end > len(items) {
+				end = len(items)
+			}
+			puts := make([]*dynamodb.WriteRequest, 0, end-start)
+			for _, item := range items[start:end] {
+				puts = append(puts, &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: item}})
+			}
+			reqs = append(reqs, explainedRequest{"BatchWriteItem", &dynamodb.BatchWriteItemInput{
+				RequestItems: map[string][]*dynamodb.WriteRequest{p.Table.Name: puts},
+			}})
+		}
+		return reqs, warnings, nil
+	}
+	if len(items) == 1 {
+		return []explainedRequest{{"PutItem", p.toPutItem(items[0], condValues)}}, warnings, nil
+	}
+	return []explainedRequest{{"TransactWriteItems", p.toTransactWrite(items, condValues)}}, warnings, nil
+}
+
+func explainUpdate(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) ([]explainedRequest, error) {
+	p, err := PrepareUpdate(ctx, tables, ast)
+	if err != nil {
+		return nil, err
+	}
+	req, err := p.NewRequest(explainArgs(p.NamedParams, p.PositionalParams))
+	if err != nil {
+		return nil, err
+	}
+	return []explainedRequest{{"UpdateItem", req}}, nil
+}
+
+func explainDelete(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) ([]explainedRequest, error) {
+	p, err := PrepareDelete(ctx, tables, ast)
+	if err != nil {
+		return nil, err
+	}
+	req, err := p.NewRequest(explainArgs(p.NamedParams, p.PositionalParams))
+	if err != nil {
+		return nil, err
+	}
+	return []explainedRequest{{"DeleteItem", req}}, nil
+}
+
+func explainCheck(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) ([]explainedRequest, []string, error) {
+	p, err := PrepareCheck(ctx, tables, ast)
+	if err != nil {
+		return nil, nil, err
+	}
+	check, err := p.NewRequest(explainArgs(p.NamedParams, p.PositionalParams))
+	if err != nil {
+		return nil, nil, err
+	}
+	req := &dynamodb.GetItemInput{TableName: check.TableName, Key: check.Key, ConsistentRead: aws.Bool(true)}
+	warnings := []string{"WHERE is evaluated by the driver on the item read, in a transaction a ConditionCheck is made instead"}
+	return []explainedRequest{{"GetItem", req}}, warnings, nil
+}
+
+// explainArgs returns args that bind each placeholder to a value marking it, so that it can be shown in the
+// formatted request.
+func explainArgs(namedParams NamedParams, positionalParams map[int]string) []driver.NamedValue {
+	var args []driver.NamedValue
+	if len(namedParams) > 0 {
+		for name := range namedParams {
+			args = append(args, driver.NamedValue{Name: name[1:], Value: explainValue(name)})
+		}
+		sort.Slice(args, func(i, j int) bool { return args[i].Name < args[j].Name })
+	}
+	// The rows of an INSERT may mix params, so the positional args follow the named ones.
+	positional := make([]driver.NamedValue, 0, len(positionalParams))
+	for ordinal := range positionalParams {
+		positional = append(positional, driver.NamedValue{Ordinal: ordinal, Value: explainValue("?" + strconv.Itoa(ordinal))})
+	}
+	sort.Slice(positional, func(i, j int) bool { return positional[i].Ordinal < positional[j].Ordinal })
+	return append(args, positional...)
+}
+
+func explainValue(placeholder string) *dynamodb.AttributeValue {
+	return &dynamodb.AttributeValue{S: aws.String(explainMarker + placeholder)}
+}
+
+// formatRequest formats the request as indented JSON, as it is sent to DynamoDB.
+func formatRequest(req interface{}) (string, error) {
+	raw, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	// The request types have no json tags, so the fields that are not set are marshaled as null. Decoding the
+	// request drops them, and sorts the fields by name.
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(omitNulls(doc)); err != nil {
+		return "", err
+	}
+	raw = explainValueRegexp.ReplaceAll(bytes.TrimSpace(buf.Bytes()), []byte(`"$1"`))
+	raw = explainItemRegexp.ReplaceAll(raw, []byte(`"$1"`))
+	buf = &bytes.Buffer{}
+	if err := json.Indent(buf, raw, "", "  "); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// omitNulls removes the null fields of the objects in a decoded JSON document.
+func omitNulls(doc interface{}) interface{} {
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		for k, v := range doc {
+			if v == nil {
+				delete(doc, k)
+			} else {
+				doc[k] = omitNulls(v)
+			}
+		}
+	case []interface{}:
+		for i, v := range doc {
+			doc[i] = omitNulls(v)
+		}
+	}
+	return doc
+}
@@ -0,0 +1,148 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+// fixtureTables describes the movies table.
+type fixtureTables struct {
+	dynamodbiface.DynamoDBAPI
+}
+
+func (d *fixtureTables) DescribeTableWithContext(ctx aws.Context, req *dynamodb.DescribeTableInput, opts ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	return &dynamodb.DescribeTableOutput{Table: &dynamodb.TableDescription{
+		TableName:            req.TableName,
+		KeySchema:            fixtures.Movies.Create.KeySchema,
+		AttributeDefinitions: fixtures.Movies.Create.AttributeDefinitions,
+	}}, nil
+}
+
+func TestExplain(t *testing.T) {
+	explain := func(t *testing.T, query string) (*ShowResult, error) {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		stmt, err := PrepareExplain(context.Background(), schema.NewTableLoader(&fixtureTables{}), ast.Explain)
+		if err != nil {
+			return nil, err
+		}
+		return stmt.Show(context.Background(), nil)
+	}
+
+	t.Run("query with filter", func(t *testing.T) {
+		result, err := explain(t, `EXPLAIN SELECT * FROM movies WHERE title = :title AND info.rating > 5 LIMIT 10`)
+		require.NoError(t, err)
+		require.Equal(t, []string{"operation", "request", "warnings"}, result.Columns)
+		require.Equal(t, [][]driver.Value{{
+			"Query",
+			`{
+  "ExpressionAttributeValues": {
+    ":_gen1": {
+      "N": "5"
+    },
+    ":title": ":title"
+  },
+  "FilterExpression": "info.rating > :_gen1",
+  "KeyConditionExpression": "title = :title",
+  "TableName": "movies"
+}`,
+			"filter on non-key attributes will consume read capacity for skipped items\n" +
+				"LIMIT not pushed down because a filter is present, so pages are read until enough items match",
+		}}, result.Rows)
+	})
+
+	t.Run("get item", func(t *testing.T) {
+		result, err := explain(t, `EXPLAIN SELECT title FROM movies WHERE title = ? AND year = ?`)
+		require.NoError(t, err)
+		require.Equal(t, [][]driver.Value{{
+			"GetItem",
+			`{
+  "Key": {
+    "title": "?1",
+    "year": "?2"
+  },
+  "ProjectionExpression": "title",
+  "TableName": "movies"
+}`,
+			nil,
+		}}, result.Rows)
+	})
+
+	t.Run("query fan out", func(t *testing.T) {
+		result, err := explain(t, `EXPLAIN SELECT * FROM movies WHERE title IN (:a, :b)`)
+		require.NoError(t, err)
+		require.Len(t, result.Rows, 2)
+		require.Contains(t, result.Rows[1][1], `":_gen1": ":b"`)
+		require.Equal(t, "the partition key is matched by 2 values, so a Query is made for each", result.Rows[0][2])
+	})
+
+	t.Run("insert", func(t *testing.T) {
+		result, err := explain(t, `EXPLAIN INSERT INTO movies VALUES (?)`)
+		require.NoError(t, err)
+		require.Equal(t, [][]driver.Value{{
+			"PutItem",
+			`{
+  "ConditionExpression": "attribute_not_exists(title)",
+  "Item": "?1",
+  "TableName": "movies"
+}`,
+			"a list of items bound to ?1 is written with TransactWriteItems",
+		}}, result.Rows)
+
+		result, err = explain(t, `EXPLAIN REPLACE INTO movies VALUES ('{"title": "Big", "year": 1988}'), ('{"title": "Up", "year": 2009}') NOT ATOMIC`)
+		require.NoError(t, err)
+		require.Equal(t, "BatchWriteItem", result.Rows[0][0])
+
+		result, err = explain(t, `EXPLAIN INSERT INTO movies (title, year, plot) VALUES (?, ?, 'Fire')`)
+		require.NoError(t, err)
+		require.Equal(t, "PutItem", result.Rows[0][0])
+		require.Contains(t, result.Rows[0][1], `"title": "?1"`)
+		require.Contains(t, result.Rows[0][1], `"S": "Fire"`)
+
+		result, err = explain(t, `EXPLAIN INSERT INTO movies VALUES ({"title": :title, "year": 1988, "info": {"genres": [:genre]}})`)
+		require.NoError(t, err)
+		require.Equal(t, "PutItem", result.Rows[0][0])
+		require.Contains(t, result.Rows[0][1], `"title": ":title"`)
+		require.Contains(t, result.Rows[0][1], `":genre"`)
+	})
+
+	t.Run("insert on conflict", func(t *testing.T) {
+		result, err := explain(t, `EXPLAIN INSERT INTO movies VALUES (:movie) ON CONFLICT DO UPDATE SET info.rating = :rating`)
+		require.NoError(t, err)
+		require.Len(t, result.Rows, 2)
+		require.Equal(t, "PutItem", result.Rows[0][0])
+		require.Contains(t, result.Rows[0][1], `"ConditionExpression": "attribute_not_exists(title)"`)
+		require.Contains(t, result.Rows[0][2], "items that exist are updated once the items that do not are put")
+		require.Equal(t, "UpdateItem", result.Rows[1][0])
+		require.Contains(t, result.Rows[1][1], `"title": ":movie.title"`)
+		require.Contains(t, result.Rows[1][1], `"UpdateExpression": "SET info.rating = :rating"`)
+		require.Contains(t, result.Rows[1][1], `"ConditionExpression": "attribute_exists(#_key)"`)
+
+		result, err = explain(t, `EXPLAIN INSERT INTO movies VALUES (?) ON CONFLICT DO NOTHING`)
+		require.NoError(t, err)
+		require.Contains(t, result.Rows[0][2], "a transaction canceled by existing items is retried without them")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		result, err := explain(t, `EXPLAIN UPDATE movies SET info.rating = :rating WHERE title = :title AND year = :year`)
+		require.NoError(t, err)
+		require.Equal(t, "UpdateItem", result.Rows[0][0])
+		require.Contains(t, result.Rows[0][1], `"UpdateExpression": "SET info.rating = :rating"`)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := explain(t, `EXPLAIN DROP TABLE movies`)
+		require.EqualError(t, err, "EXPLAIN only supports SELECT, SCAN, INSERT, REPLACE, UPDATE, DELETE and CHECK")
+	})
+}
@@ -0,0 +1,167 @@
+package querybuilder
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// maxBatchGetKeys is the maximum number of keys DynamoDB allows in a BatchGetItem request.
+const maxBatchGetKeys = 100
+
+var errTupleIn = errors.New("(...) IN (...) may only be used to match the partition key and sort key of a table, with no other conditions, such as: WHERE (hash, sort) IN ((:hash1, :sort1), (:hash2, :sort2))")
+
+// extractItemKeys returns the primary keys of the items matched by the WHERE clause, if it matches items by their
+// full primary key and nothing else. That is one of:
+//
+//	WHERE hash = :hash AND sort = :sort
+//	WHERE (hash, sort) IN ((:hash1, :sort1), (:hash2, :sort2))
+//	WHERE hash IN (:hash1, :hash2)  -- for tables without a sort key
+//
+// Each key maps key attribute names to the placeholders holding their values. batch is false if there is a single
+// key matched by equality. prepareValuesAndPlaceholders must have been called on the expression first.
+func extractItemKeys(ctx *Context, where *parser.AndExpression) (keys []map[string]string, batch bool, err error) {
+	if where == nil {
+		return nil, false, nil
+	}
+	if len(where.And) == 1 {
+		term := where.And[0]
+		if term.TupleIn != nil {
+			keys, err := tupleKeys(ctx, term.TupleIn)
+			return keys, true, err
+		}
+		if ctx.SortKey == "" && term.Operand != nil && term.Operand.Operand.String() == ctx.HashKey &&
+			term.Operand.ConditionRHS.In != nil {
+			for _, value := range term.Operand.ConditionRHS.In.Values {
+				keys = append(keys, map[string]string{ctx.HashKey: *value.PlaceHolder})
+			}
+			return keys, true, nil
+		}
+	}
+	key := make(map[string]string, 2)
+	for _, term := range where.And {
+		name, placeholder := keyEquality(ctx, term)
+		if name == "" {
+			return nil, false, nil
+		}
+		if _, ok := key[name]; ok {
+			return nil, false, nil
+		}
+		key[name] = placeholder
+	}
+	if _, ok := key[ctx.HashKey]; !ok {
+		return nil, false, nil
+	}
+	if _, ok := key[ctx.SortKey]; ctx.SortKey != "" && !ok {
+		return nil, false, nil
+	}
+	return []map[string]string{key}, false, nil
+}
+
+func tupleKeys(ctx *Context, in *parser.TupleIn) ([]map[string]string, error) {
+	if ctx.SortKey == "" || len(in.Operands) != 2 || in.Operands[0] == in.Operands[1] ||
+		!ctx.IsKey(in.Operands[0]) || !ctx.IsKey(in.Operands[1]) {
+		return nil, errTupleIn
+	}
+	keys := make([]map[string]string, 0, len(in.Values))
+	for _, tuple := range in.Values {
+		if len(tuple.Values) != len(in.Operands) {
+			return nil, fmt.Errorf("expected %d values in each tuple of IN, got %d", len(in.Operands), len(tuple.Values))
+		}
+		key := make(map[string]string, len(in.Operands))
+		for i, name := range in.Operands {
+			key[name] = *tuple.Values[i].PlaceHolder
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// prepareGet prepares a SELECT that matches items by their full primary keys. The items are looked up with GetItem
+// or BatchGetItem, which are cheaper than a Query.
+func prepareGet(ctx *Context, ast *parser.Select, keys []map[string]string, batch bool) (*PreparedQuery, error) {
+	projectionExpr, err := buildOptionalProjectionExpression(ctx, ast.Projection)
+	if err != nil {
+		return nil, err
+	}
+	if len(ctx.PositionalParams) > 0 && len(ctx.NamedParams) > 0 {
+		return nil, errors.New("cannot mix positional params (?) with named params (:param)")
+	}
+	prepared := &PreparedQuery{
+		Keys:             keys,
+		Columns:          ast.Projection.Columns,
+		NamedParams:      ctx.NamedParams,
+		PositionalParams: ctx.PositionalParams,
+		FixedParams:      ctx.FixedParams,
+	}
+	if !batch {
+		prepared.GetItem = &dynamodb.GetItemInput{
+			TableName:                &ast.From,
+			ProjectionExpression:     projectionExpr,
+			ExpressionAttributeNames: ctx.ExpressionAttributeNames(),
+		}
+		return prepared, nil
+	}
+	if ast.Limit != nil {
+		prepared.Limit = *ast.Limit
+	}
+	prepared.BatchGet = &dynamodb.BatchGetItemInput{
+		RequestItems: map[string]*dynamodb.KeysAndAttributes{
+			ast.From: {
+				ProjectionExpression:     projectionExpr,
+				ExpressionAttributeNames: ctx.ExpressionAttributeNames(),
+			},
+		},
+	}
+	return prepared, nil
+}
+
+func (pq *PreparedQuery) NewGetItemRequest(args []driver.NamedValue) (*dynamodb.GetItemInput, error) {
+	values, err := bindArgs(pq.FixedParams, pq.NamedParams, pq.PositionalParams, args)
+	if err != nil {
+		return nil, err
+	}
+	req := *pq.GetItem
+	req.Key = bindKey(pq.Keys[0], values)
+	return &req, nil
+}
+
+// NewBatchGetRequests binds the distinct keys of the items to get, and splits them into requests of up to
+// maxBatchGetKeys keys each.
+func (pq *PreparedQuery) NewBatchGetRequests(args []driver.NamedValue) ([]*dynamodb.BatchGetItemInput, error) {
+	values, err := bindArgs(pq.FixedParams, pq.NamedParams, pq.PositionalParams, args)
+	if err != nil {
+		return nil, err
+	}
+	// BatchGetItem rejects requests with duplicate keys.
+	keys := make([]map[string]*dynamodb.AttributeValue, 0, len(pq.Keys))
+	seen := make(map[string]bool, len(pq.Keys))
+	for _, key := range pq.Keys {
+		bound := bindKey(key, values)
+		id := keyString(bound)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		keys = append(keys, bound)
+	}
+	var reqs []*dynamodb.BatchGetItemInput
+	for table, template := range pq.BatchGet.RequestItems {
+		for start := 0; start < len(keys); start += maxBatchGetKeys {
+			end := start + maxBatchGetKeys
+			if end > len(keys) {
+				end = len(keys)
+			}
+			chunk := *template
+			chunk.Keys = keys[start:end]
+			reqs = append(reqs, &dynamodb.BatchGetItemInput{
+				RequestItems: map[string]*dynamodb.KeysAndAttributes{table: &chunk},
+			})
+		}
+	}
+	return reqs, nil
+}
@@ -0,0 +1,106 @@
+package querybuilder
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+func TestPrepareGet(t *testing.T) {
+	movies := schema.NewTableFromCreate(fixtures.Movies.Create)
+	users := schema.NewTableFromCreate(&dynamodb.CreateTableInput{
+		TableName: aws.String("users"),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+		},
+	})
+	prepareQuery := func(table *schema.Table, query string) *PreparedQuery {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		prepared, err := prepare(table, ast.Select)
+		require.NoError(t, err)
+		return prepared
+	}
+
+	t.Run("get item", func(t *testing.T) {
+		prepared := prepareQuery(movies, `SELECT * FROM movies WHERE year = ? AND title = ?`)
+		req, err := prepared.NewGetItemRequest([]driver.NamedValue{
+			{Ordinal: 1, Value: int64(1988)},
+			{Ordinal: 2, Value: "Big"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &dynamodb.GetItemInput{
+			TableName: aws.String("movies"),
+			Key: map[string]*dynamodb.AttributeValue{
+				"title": {S: aws.String("Big")},
+				"year":  {N: aws.String("1988")},
+			},
+		}, req)
+	})
+
+	t.Run("batch get chunks distinct keys", func(t *testing.T) {
+		query := `SELECT title FROM movies WHERE (title, year) IN (`
+		var args []driver.NamedValue
+		for i := 0; i < 250; i++ {
+			if i != 0 {
+				query += ", "
+			}
+			query += "(?, ?)"
+			// Every fifth key is a duplicate of the one before it.
+			n := i - (i+1)/5
+			args = append(args,
+				driver.NamedValue{Ordinal: len(args) + 1, Value: fmt.Sprintf("movie %d", n)},
+				driver.NamedValue{Ordinal: len(args) + 2, Value: int64(2000)})
+		}
+		prepared := prepareQuery(movies, query+")")
+		reqs, err := prepared.NewBatchGetRequests(args)
+		require.NoError(t, err)
+		require.Len(t, reqs, 2)
+		require.Len(t, reqs[0].RequestItems["movies"].Keys, 100)
+		require.Len(t, reqs[1].RequestItems["movies"].Keys, 100)
+		require.Equal(t, aws.String("title"), reqs[1].RequestItems["movies"].ProjectionExpression)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			"title": {S: aws.String("movie 199")},
+			"year":  {N: aws.String("2000")},
+		}, reqs[1].RequestItems["movies"].Keys[99])
+	})
+
+	t.Run("batch get compares numbers by value", func(t *testing.T) {
+		prepared := prepareQuery(movies, `SELECT * FROM movies WHERE (title, year) IN (("Big", ?), ("Big", ?), ("Big", ?), ("Big", ?))`)
+		reqs, err := prepared.NewBatchGetRequests([]driver.NamedValue{
+			{Ordinal: 1, Value: int64(1988)},
+			{Ordinal: 2, Value: &dynamodb.AttributeValue{N: aws.String("1988.0")}},
+			{Ordinal: 3, Value: &dynamodb.AttributeValue{N: aws.String("19.88e2")}},
+			{Ordinal: 4, Value: int64(1989)},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []map[string]*dynamodb.AttributeValue{
+			{"title": {S: aws.String("Big")}, "year": {N: aws.String("1988")}},
+			{"title": {S: aws.String("Big")}, "year": {N: aws.String("1989")}},
+		}, reqs[0].RequestItems["movies"].Keys)
+	})
+
+	t.Run("batch get by partition key", func(t *testing.T) {
+		prepared := prepareQuery(users, `SELECT * FROM users WHERE id IN ("a", "b")`)
+		reqs, err := prepared.NewBatchGetRequests(nil)
+		require.NoError(t, err)
+		require.Equal(t, []*dynamodb.BatchGetItemInput{{
+			RequestItems: map[string]*dynamodb.KeysAndAttributes{
+				"users": {
+					Keys: []map[string]*dynamodb.AttributeValue{
+						{"id": {S: aws.String("a")}},
+						{"id": {S: aws.String("b")}},
+					},
+				},
+			},
+		}}, reqs)
+	})
+}
@@ -0,0 +1,43 @@
+package querybuilder
+
+import (
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// PrepareCreateIndex prepares CREATE INDEX, which is the same as ALTER TABLE ... ADD GLOBAL INDEX.
+func PrepareCreateIndex(tables *schema.TableLoader, create *parser.CreateIndex) (*PreparedAlterTable, error) {
+	gsi := &parser.GlobalSecondaryIndex{
+		Name:                  create.Name,
+		PartitionKey:          create.PartitionKey.Name,
+		Projection:            create.Projection,
+		ProvisionedThroughput: create.ProvisionedThroughput,
+	}
+	keys := []*parser.IndexKey{create.PartitionKey}
+	if create.SortKey != nil {
+		gsi.SortKey = create.SortKey.Name
+		keys = append(keys, create.SortKey)
+	}
+	alter := &parser.AlterTable{
+		Table:   create.Table,
+		Actions: []*parser.AlterTableAction{{AddIndex: gsi}},
+		Wait:    create.Wait,
+	}
+	for _, key := range keys {
+		if key.Type != "" {
+			alter.Actions = append(alter.Actions, &parser.AlterTableAction{
+				AddAttr: &parser.TableAttr{Name: key.Name, Type: key.Type},
+			})
+		}
+	}
+	return PrepareAlterTable(tables, alter)
+}
+
+// PrepareDropIndex prepares DROP INDEX, which is the same as ALTER TABLE ... DROP INDEX.
+func PrepareDropIndex(tables *schema.TableLoader, drop *parser.DropIndex) (*PreparedAlterTable, error) {
+	return PrepareAlterTable(tables, &parser.AlterTable{
+		Table:   drop.Table,
+		Actions: []*parser.AlterTableAction{{DropIndex: &drop.Name}},
+		Wait:    drop.Wait,
+	})
+}
@@ -7,23 +7,81 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
 
 	"github.com/alecthomas/repr"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/mightyguava/dynamosql/parser"
 	"github.com/mightyguava/dynamosql/schema"
 )
 
+const (
+	// maxBatchWriteItems is the maximum number of items DynamoDB allows in a BatchWriteItem request.
+	maxBatchWriteItems = 25
+	// maxBatchWriteConcurrency is the maximum number of concurrent BatchWriteItem requests made by a NOT ATOMIC insert.
+	maxBatchWriteConcurrency = 8
+)
+
 type PreparedInsert struct {
 	Table       *schema.Table
 	Placeholder string
 	Values      []map[string]*dynamodb.AttributeValue
 	Returning   *string
 	Replace     bool
+	NotAtomic   bool
+
+	// Templates are the JSON objects in VALUES that contain placeholders, by the index of their item in Values, which
+	// is nil until the placeholders are bound.
+	Templates []*parser.JSONValue
+
+	// The columns of an INSERT with a column list, and the values of each row. Positional placeholders in the values
+	// are renamed to the generated placeholders they are bound to.
+	Columns []string
+	Rows    [][]*parser.JSONValue
+
+	// DoNothing skips items whose key already exists, from ON CONFLICT DO NOTHING.
+	DoNothing bool
+	// The SET actions from ON CONFLICT DO UPDATE, and the paths they update. An INSERT with them writes each item
+	// with UpdateItem, merging its attributes into the existing item before applying the actions.
+	UpsertSet   *string
+	UpsertNames map[string]*string
+	UpsertPaths []string
+
+	// The ConditionExpression built from the WHERE clause of a REPLACE. The params are those bound in WHERE or in
+	// ON CONFLICT DO UPDATE.
+	Condition        *string
+	ConditionNames   map[string]*string
+	NamedParams      NamedParams
+	PositionalParams map[int]string
+	FixedParams      map[string]interface{}
+}
+
+// ErrConditionFailed is returned when the WHERE clause of a REPLACE does not hold for the existing item. Test for it
+// with errors.Is.
+var ErrConditionFailed = errors.New("condition in WHERE failed")
+
+// conditionFailedError wraps the error from DynamoDB, so that it is both ErrConditionFailed and the AWS error.
+type conditionFailedError struct {
+	err error
+}
+
+func (e *conditionFailedError) Error() string {
+	return ErrConditionFailed.Error() + ": " + e.err.Error()
+}
+
+func (e *conditionFailedError) Is(target error) bool {
+	return target == ErrConditionFailed
+}
+
+func (e *conditionFailedError) Unwrap() error {
+	return e.err
 }
 
 func PrepareInsert(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) (*PreparedInsert, error) {
@@ -34,6 +92,12 @@ func PrepareInsert(ctx context.Context, tables *schema.TableLoader, ast *parser.
 		if !ins.Replace && ins.Returning != nil && *ins.Returning != "NONE" {
 			return nil, errors.New("RETURNING is not allowed on INSERT")
 		}
+		if ins.NotAtomic && !ins.Replace {
+			return nil, errors.New("NOT ATOMIC is only allowed on REPLACE, since BatchWriteItem cannot check for existing items")
+		}
+		if ins.NotAtomic && ins.Returning != nil && *ins.Returning != "NONE" {
+			return nil, errors.New("RETURNING is not allowed with NOT ATOMIC")
+		}
 	default:
 		return nil, fmt.Errorf("expected INSERT but got %s", repr.String(ast))
 	}
@@ -41,47 +105,267 @@ func PrepareInsert(ctx context.Context, tables *schema.TableLoader, ast *parser.
 	if err != nil {
 		return nil, err
 	}
-	var literals []string
-	var usePlaceholder bool
-	var placeholder string
+	var (
+		usePlaceholder bool
+		placeholder    string
+		values         []map[string]*dynamodb.AttributeValue
+		templates      []*parser.JSONValue
+		hasTemplates   bool
+	)
 	for _, v := range ins.Values {
+		var item map[string]*dynamodb.AttributeValue
+		var template *parser.JSONValue
 		switch {
 		case v.PositionalPlaceholder:
 			usePlaceholder = true
+			continue
 		case v.PlaceHolder != nil:
 			usePlaceholder = true
 			placeholder = *v.PlaceHolder
+			continue
 		case v.Object != nil:
-			literals = append(literals, v.Object.String())
+			doc := &parser.JSONValue{Object: v.Object}
+			if hasPlaceholders(doc) {
+				template = doc
+				hasTemplates = true
+				break
+			}
+			av, err := bindJSON(doc, nil)
+			if err != nil {
+				return nil, err
+			}
+			item = av.M
 		case v.Str != nil:
-			literals = append(literals, *v.Str)
+			item, err = jsonStringToDynamodbMap(*v.Str)
+			if err != nil {
+				return nil, err
+			}
 		default:
 			return nil, fmt.Errorf("VALUES expression may must be a placeholder or string, but was %s", repr.String(v))
 		}
+		values = append(values, item)
+		templates = append(templates, template)
 	}
 	if usePlaceholder && len(ins.Values) > 1 {
 		return nil, errors.New("when using placeholder parameters, INSERT may contain exactly one placeholder")
 	}
-
-	var values []map[string]*dynamodb.AttributeValue
-	if len(literals) > 0 {
-		values = make([]map[string]*dynamodb.AttributeValue, 0, len(literals))
-		for _, l := range literals {
-			av, err := jsonStringToDynamodbMap(l)
-			if err != nil {
-				return nil, err
-			}
-			values = append(values, av)
-		}
+	if ins.Where != nil && !ins.Replace {
+		return nil, errors.New("WHERE is only allowed on REPLACE, since INSERT only writes items that do not exist")
+	}
+	if ins.Where != nil && ins.NotAtomic {
+		return nil, errors.New("WHERE is not allowed with NOT ATOMIC, since BatchWriteItem cannot check conditions")
+	}
+	if ins.OnConflict != nil && ins.Replace {
+		return nil, errors.New("ON CONFLICT is only allowed on INSERT, since REPLACE overwrites existing items")
 	}
 
-	return &PreparedInsert{
+	prepared := &PreparedInsert{
 		Table:       table,
 		Placeholder: placeholder,
 		Values:      values,
 		Returning:   ins.Returning,
 		Replace:     ins.Replace,
-	}, nil
+		NotAtomic:   ins.NotAtomic,
+	}
+	if hasTemplates {
+		prepared.Templates = templates
+	}
+	var upsert *parser.Update
+	if ins.OnConflict != nil {
+		prepared.DoNothing = ins.OnConflict.DoNothing
+		if len(ins.OnConflict.Update) > 0 {
+			upsert = &parser.Update{Set: ins.OnConflict.Update}
+			if err := checkUpdateActions(upsert); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if ins.Where != nil || upsert != nil || len(ins.Columns) > 0 || hasTemplates {
+		ctx := NewContext(table, "")
+		positionalItems := usePlaceholder && placeholder == ""
+		if positionalItems {
+			// The items are bound to the first arg, so the other params start from the second.
+			ctx.NextPositionalParam()
+		}
+		for _, template := range prepared.Templates {
+			if template != nil {
+				prepareJSON(ctx, template)
+			}
+		}
+		if len(ins.Columns) > 0 {
+			rows, err := prepareRows(ctx, ins)
+			if err != nil {
+				return nil, err
+			}
+			prepared.Columns = ins.Columns
+			prepared.Rows = rows
+		}
+		for _, node := range []parser.Node{ins.OnConflict, ins.Where} {
+			if err := prepareValuesAndPlaceholders(ctx, node); err != nil {
+				return nil, err
+			}
+		}
+		// The rows of a column list and the templates may mix params, since each placeholder in them is bound on
+		// its own, but an arg bound to all the items may not.
+		if usePlaceholder && (positionalItems || len(ctx.PositionalParams) > 0) && (placeholder != "" || len(ctx.NamedParams) > 0) {
+			return nil, errors.New("cannot mix positional params (?) with named params (:param)")
+		}
+		if _, ok := ctx.NamedParams[placeholder]; ok {
+			if upsert != nil {
+				return nil, fmt.Errorf("%s is bound to the items, and cannot be used in ON CONFLICT", placeholder)
+			}
+			return nil, fmt.Errorf("%s is bound to the items, and cannot be used in WHERE", placeholder)
+		}
+		if ins.Where != nil {
+			cond, err := buildConditionExpression(ctx, ins.Where)
+			if err != nil {
+				return nil, err
+			}
+			prepared.Condition = aws.String(cond)
+			prepared.ConditionNames = ctx.ExpressionAttributeNames()
+		}
+		if upsert != nil {
+			set, err := buildUpdateExpression(ctx, upsert)
+			if err != nil {
+				return nil, err
+			}
+			prepared.UpsertSet = aws.String(strings.TrimPrefix(set, "SET "))
+			prepared.UpsertNames = ctx.ExpressionAttributeNames()
+			for _, action := range upsert.Set {
+				prepared.UpsertPaths = append(prepared.UpsertPaths, action.Path.String())
+			}
+		}
+		prepared.NamedParams = ctx.NamedParams
+		prepared.PositionalParams = ctx.PositionalParams
+		prepared.FixedParams = ctx.FixedParams
+	}
+	return prepared, nil
+}
+
+// prepareRows returns the values in the rows of an INSERT with a column list, with their placeholders prepared.
+func prepareRows(ctx *Context, ins *parser.InsertOrReplace) ([][]*parser.JSONValue, error) {
+	columns := make(map[string]bool, len(ins.Columns))
+	for _, column := range ins.Columns {
+		if columns[column] {
+			return nil, fmt.Errorf("column %q appears more than once", column)
+		}
+		columns[column] = true
+	}
+	for _, key := range []string{ctx.HashKey, ctx.SortKey} {
+		if key != "" && !columns[key] {
+			return nil, fmt.Errorf("columns must include the key attribute %q", key)
+		}
+	}
+	rows := make([][]*parser.JSONValue, 0, len(ins.Rows))
+	for i, row := range ins.Rows {
+		if len(row.Values) != len(ins.Columns) {
+			return nil, fmt.Errorf("row %d has %d values, but there are %d columns", i+1, len(row.Values), len(ins.Columns))
+		}
+		cells := make([]*parser.JSONValue, 0, len(row.Values))
+		for _, v := range row.Values {
+			cell := &parser.JSONValue{
+				Scalar:                v.Scalar,
+				PlaceHolder:           v.PlaceHolder,
+				PositionalPlaceholder: v.PositionalPlaceholder,
+				Object:                v.Object,
+				Array:                 v.Array,
+			}
+			prepareJSON(ctx, cell)
+			cells = append(cells, cell)
+		}
+		rows = append(rows, cells)
+	}
+	return rows, nil
+}
+
+// hasPlaceholders returns true if the JSON value contains a placeholder at any depth.
+func hasPlaceholders(value *parser.JSONValue) bool {
+	found := false
+	_ = parser.Visit(value, func(node parser.Node, next func() error) error {
+		if v, ok := node.(*parser.JSONValue); ok && (v.PlaceHolder != nil || v.PositionalPlaceholder) {
+			found = true
+		}
+		return next()
+	})
+	return found
+}
+
+// prepareJSON records the placeholders in the JSON value as params, and renames positional placeholders to the
+// generated placeholders they are bound to.
+func prepareJSON(ctx *Context, value *parser.JSONValue) {
+	_ = parser.Visit(value, func(node parser.Node, next func() error) error {
+		if v, ok := node.(*parser.JSONValue); ok {
+			switch {
+			case v.PlaceHolder != nil:
+				ctx.NamedParams[*v.PlaceHolder] = Empty{}
+			case v.PositionalPlaceholder:
+				num, name := ctx.NextPositionalParam()
+				ctx.PositionalParams[num] = name
+				v.PlaceHolder = &name
+				v.PositionalPlaceholder = false
+			}
+		}
+		return next()
+	})
+}
+
+// boundValue is the value bound to a placeholder in a JSON value. It is marshaled as is.
+type boundValue struct {
+	av *dynamodb.AttributeValue
+}
+
+func (b boundValue) MarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	*av = *b.av
+	return nil
+}
+
+// bindJSON converts a JSON value in a statement to an attribute value, as if it were unmarshaled from JSON, with
+// its placeholders replaced by their bound values.
+func bindJSON(value *parser.JSONValue, values map[string]*dynamodb.AttributeValue) (*dynamodb.AttributeValue, error) {
+	v, err := jsonToInterface(value, values)
+	if err != nil {
+		return nil, err
+	}
+	return dynamodbattribute.Marshal(v)
+}
+
+func jsonToInterface(value *parser.JSONValue, values map[string]*dynamodb.AttributeValue) (interface{}, error) {
+	switch {
+	case value.Object != nil:
+		m := make(map[string]interface{}, len(value.Object.Entries))
+		for _, entry := range value.Object.Entries {
+			v, err := jsonToInterface(entry.Value, values)
+			if err != nil {
+				return nil, err
+			}
+			m[entry.Key] = v
+		}
+		return m, nil
+	case value.Array != nil:
+		l := make([]interface{}, 0, len(value.Array.Entries))
+		for _, entry := range value.Array.Entries {
+			v, err := jsonToInterface(entry, values)
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, v)
+		}
+		return l, nil
+	case value.PlaceHolder != nil:
+		av, ok := values[*value.PlaceHolder]
+		if !ok {
+			return nil, fmt.Errorf("missing argument for binding %q", *value.PlaceHolder)
+		}
+		return boundValue{av}, nil
+	case value.Number != nil:
+		return *value.Number, nil
+	case value.Str != nil:
+		return *value.Str, nil
+	case value.Boolean != nil:
+		return bool(*value.Boolean), nil
+	default:
+		return nil, nil
+	}
 }
 
 type driverResult struct {
@@ -99,33 +383,23 @@ func (i driverResult) RowsAffected() (int64, error) {
 }
 
 func (p *PreparedInsert) Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
-	if len(args) > 0 && len(p.Values) > 0 {
-		return nil, errors.New("no arguments expected")
+	values, condValues, err := p.items(args)
+	if err != nil {
+		return nil, err
 	}
-	var values []map[string]*dynamodb.AttributeValue
-	if len(p.Values) > 0 {
-		values = p.Values
-	} else {
-		if len(args) > 1 && len(p.Values) == 0 {
-			return nil, errors.New("too many arguments")
-		}
-		arg := args[0]
-		if arg.Name != p.Placeholder {
-			return nil, fmt.Errorf("unexpected named argument %q", arg.Name)
-		}
-		var err error
-		values, err = argToListOfMaps(arg.Value)
-		if err != nil {
-			return nil, err
-		}
+	if p.NotAtomic {
+		return p.batchWrite(ctx, dynamo, values)
 	}
-
-	if len(values) == 0 {
-		return nil, errors.New("no values to insert")
+	if p.UpsertSet != nil {
+		return p.upsert(ctx, dynamo, values, condValues)
 	}
 	if len(values) == 1 {
-		resp, err := dynamo.PutItem(p.toPutItem(values[0]))
-		if err != nil {
+		resp, err := dynamo.PutItem(p.toPutItem(values[0], condValues))
+		if p.Condition != nil && isConditionalCheckFailed(err) {
+			return nil, &conditionFailedError{err}
+		} else if p.DoNothing && isConditionalCheckFailed(err) {
+			return &DriverResult{count: 0}, nil
+		} else if err != nil {
 			return nil, err
 		}
 		return &DriverResult{
@@ -136,24 +410,437 @@ func (p *PreparedInsert) Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAP
 	if p.Returning != nil && *p.Returning != "NONE" {
 		return nil, errors.New("cannot use RETURNING with more than 1 item")
 	}
-	_, err := dynamo.TransactWriteItems(p.toTransactWrite(values))
+	if p.DoNothing {
+		existing, err := p.insertNew(ctx, dynamo, values)
+		if err != nil {
+			return nil, err
+		}
+		count := 0
+		for _, exists := range existing {
+			if !exists {
+				count++
+			}
+		}
+		return &DriverResult{count: count}, nil
+	}
+	_, err = dynamo.TransactWriteItems(p.toTransactWrite(values, condValues))
+	if p.Condition != nil && isTransactionConditionFailed(err) {
+		return nil, &conditionFailedError{err}
+	} else if err != nil {
+		return nil, err
+	}
+	return &DriverResult{count: len(values)}, nil
+}
+
+func (p *PreparedInsert) Transact(tx *Transaction, args []driver.NamedValue) (*DriverResult, error) {
+	if p.Returning != nil && *p.Returning != "NONE" {
+		return nil, errReturningInTransaction
+	}
+	if p.NotAtomic {
+		return nil, errors.New("NOT ATOMIC is not allowed in transactions")
+	}
+	if p.DoNothing {
+		return nil, errors.New("ON CONFLICT DO NOTHING is not allowed in transactions, since an existing item cancels the transaction")
+	}
+	if p.UpsertSet != nil {
+		return nil, errors.New("ON CONFLICT DO UPDATE is not allowed in transactions, since whether an item exists is only known once it is written")
+	}
+	values, condValues, err := p.items(args)
 	if err != nil {
 		return nil, err
 	}
+	writes := p.toTransactWrite(values, condValues).TransactItems
+	for i, item := range writes {
+		key, err := p.key(values[i])
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.add(p.Table.Name, key, item); err != nil {
+			return nil, err
+		}
+	}
 	return &DriverResult{count: len(values)}, nil
 }
 
-func (p *PreparedInsert) toTransactWrite(items []map[string]*dynamodb.AttributeValue) *dynamodb.TransactWriteItemsInput {
+// batchWrite writes the items with BatchWriteItem, making up to maxBatchWriteConcurrency requests of up to
+// maxBatchWriteItems items at a time. The items are not written atomically. If the same key appears more than once,
+// only the last item with that key is written, as if the items were written in order.
+func (p *PreparedInsert) batchWrite(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, items []map[string]*dynamodb.AttributeValue) (*DriverResult, error) {
+	var puts []*dynamodb.WriteRequest
+	index := make(map[string]int, len(items))
+	for _, item := range items {
+		key, err := p.key(item)
+		if err != nil {
+			return nil, err
+		}
+		put := &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: item}}
+		id := keyString(key)
+		if i, ok := index[id]; ok {
+			puts[i] = put
+			continue
+		}
+		index[id] = len(puts)
+		puts = append(puts, put)
+	}
+
+	var written int64
+	chunks := make(chan []*dynamodb.WriteRequest)
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		defer close(chunks)
+		for start := 0; start < len(puts); start += maxBatchWriteItems {
+			end := start + maxBatchWriteItems
+			if end > len(puts) {
+				end = len(puts)
+			}
+			select {
+			case chunks <- puts[start:end]:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+		}
+		return nil
+	})
+	for i := 0; i < maxBatchWriteConcurrency; i++ {
+		g.Go(func() error {
+			for chunk := range chunks {
+				req := &dynamodb.BatchWriteItemInput{
+					RequestItems: map[string][]*dynamodb.WriteRequest{p.Table.Name: chunk},
+				}
+				if err := BatchWrite(gctx, dynamo, req); err != nil {
+					return err
+				}
+				atomic.AddInt64(&written, int64(len(chunk)))
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("wrote %d of %d items: %w", atomic.LoadInt64(&written), len(puts), err)
+	}
+	return &DriverResult{count: len(puts)}, nil
+}
+
+// insertNew writes the items whose key does not exist yet in one transaction, for ON CONFLICT, and returns which of
+// the items exist. When the transaction is canceled because some of the items exist, it is retried without them.
+func (p *PreparedInsert) insertNew(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, items []map[string]*dynamodb.AttributeValue) ([]bool, error) {
+	existing := make([]bool, len(items))
+	remaining := make([]int, len(items))
+	for i := range remaining {
+		remaining[i] = i
+	}
+	for len(remaining) > 0 {
+		puts := make([]map[string]*dynamodb.AttributeValue, len(remaining))
+		for j, i := range remaining {
+			puts[j] = items[i]
+		}
+		_, err := dynamo.TransactWriteItemsWithContext(ctx, p.toTransactWrite(puts, nil))
+		if err == nil {
+			break
+		}
+		failed := existingItems(err, len(puts))
+		if failed == nil {
+			return nil, err
+		}
+		next := make([]int, 0, len(remaining))
+		for j, i := range remaining {
+			if failed[j] {
+				existing[i] = true
+			} else {
+				next = append(next, i)
+			}
+		}
+		remaining = next
+	}
+	return existing, nil
+}
+
+// existingItems returns which of the n items in a canceled transaction failed the attribute_not_exists condition.
+// It returns nil if the transaction failed for any other reason.
+func existingItems(err error, n int) []bool {
+	var canceled *dynamodb.TransactionCanceledException
+	if !errors.As(err, &canceled) || len(canceled.CancellationReasons) != n {
+		return nil
+	}
+	existing := make([]bool, n)
+	for i, reason := range canceled.CancellationReasons {
+		switch aws.StringValue(reason.Code) {
+		case "None":
+		case "ConditionalCheckFailed":
+			existing[i] = true
+		default:
+			return nil
+		}
+	}
+	return existing
+}
+
+// upsert writes the items for ON CONFLICT DO UPDATE. The items whose key does not exist yet are put as they are, and
+// the others are then updated with UpdateItem, in one transaction if there is more than one.
+func (p *PreparedInsert) upsert(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, items []map[string]*dynamodb.AttributeValue, setValues map[string]*dynamodb.AttributeValue) (*DriverResult, error) {
+	var existing []bool
+	if len(items) == 1 {
+		_, err := dynamo.PutItemWithContext(ctx, p.toPutItem(items[0], nil))
+		if err != nil && !isConditionalCheckFailed(err) {
+			return nil, err
+		}
+		existing = []bool{err != nil}
+	} else {
+		var err error
+		existing, err = p.insertNew(ctx, dynamo, items)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var updates []*dynamodb.Update
+	for i, item := range items {
+		if !existing[i] {
+			continue
+		}
+		update, err := p.toUpdate(item, setValues)
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, update)
+	}
+	switch len(updates) {
+	case 0:
+	case 1:
+		if _, err := dynamo.UpdateItemWithContext(ctx, toUpdateItem(updates[0])); err != nil {
+			return nil, err
+		}
+	default:
+		writes := make([]*dynamodb.TransactWriteItem, 0, len(updates))
+		for _, update := range updates {
+			writes = append(writes, &dynamodb.TransactWriteItem{Update: update})
+		}
+		if _, err := dynamo.TransactWriteItemsWithContext(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: writes}); err != nil {
+			return nil, err
+		}
+	}
+	return &DriverResult{count: len(items)}, nil
+}
+
+// toUpdate returns an update of an existing item that sets each attribute of the item, followed by the SET actions of
+// ON CONFLICT DO UPDATE. Attributes of the item that overlap a path in the SET actions are left to the actions. The
+// attributes are always substituted, since the item may contain any attribute name. The update fails if the item no
+// longer exists, rather than creating it with only the attributes that are set.
+func (p *PreparedInsert) toUpdate(item map[string]*dynamodb.AttributeValue, setValues map[string]*dynamodb.AttributeValue) (*dynamodb.Update, error) {
+	key, err := p.key(item)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]*string, len(item)+len(p.UpsertNames))
+	for k, v := range p.UpsertNames {
+		names[k] = v
+	}
+	values := make(map[string]*dynamodb.AttributeValue, len(item)+len(setValues))
+	for k, v := range setValues {
+		values[k] = v
+	}
+	attrs := make([]string, 0, len(item))
+	for attr := range item {
+		if _, ok := key[attr]; !ok && !p.setByUpsert(attr) {
+			attrs = append(attrs, attr)
+		}
+	}
+	sort.Strings(attrs)
+	sets := make([]string, 0, len(attrs)+1)
+	for i, attr := range attrs {
+		name, value := fmt.Sprintf("#_item%d", i+1), fmt.Sprintf(":_item%d", i+1)
+		names[name] = aws.String(attr)
+		values[value] = item[attr]
+		sets = append(sets, name+" = "+value)
+	}
+	sets = append(sets, *p.UpsertSet)
+	names["#_key"] = aws.String(p.Table.HashKey)
+	if len(values) == 0 {
+		values = nil
+	}
+	return &dynamodb.Update{
+		TableName:                 &p.Table.Name,
+		Key:                       key,
+		UpdateExpression:          aws.String("SET " + strings.Join(sets, ", ")),
+		ConditionExpression:       aws.String("attribute_exists(#_key)"),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}, nil
+}
+
+// toUpdateItem returns the UpdateItem request for an update in a transaction.
+func toUpdateItem(update *dynamodb.Update) *dynamodb.UpdateItemInput {
+	return &dynamodb.UpdateItemInput{
+		TableName:                 update.TableName,
+		Key:                       update.Key,
+		UpdateExpression:          update.UpdateExpression,
+		ConditionExpression:       update.ConditionExpression,
+		ExpressionAttributeNames:  update.ExpressionAttributeNames,
+		ExpressionAttributeValues: update.ExpressionAttributeValues,
+	}
+}
+
+// setByUpsert returns true if the attribute overlaps a path updated by ON CONFLICT DO UPDATE.
+func (p *PreparedInsert) setByUpsert(attr string) bool {
+	for _, path := range p.UpsertPaths {
+		if pathsOverlap(attr, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// key returns the primary key of an item.
+func (p *PreparedInsert) key(item map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, error) {
+	key := make(map[string]*dynamodb.AttributeValue, 2)
+	for _, name := range []string{p.Table.HashKey, p.Table.SortKey} {
+		if name == "" {
+			continue
+		}
+		if _, ok := item[name]; !ok {
+			return nil, fmt.Errorf("item is missing key attribute %q", name)
+		}
+		key[name] = item[name]
+	}
+	return key, nil
+}
+
+// items returns the items to insert, from either the VALUES literals or the args, and the values of the args bound
+// in the WHERE clause.
+func (p *PreparedInsert) items(args []driver.NamedValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, error) {
+	if len(p.Columns) > 0 {
+		return p.rowItems(args)
+	}
+	var values []map[string]*dynamodb.AttributeValue
+	if len(p.Values) > 0 {
+		values = p.Values
+	} else {
+		var itemsArg *driver.NamedValue
+		var rest []driver.NamedValue
+		for i, arg := range args {
+			if itemsArg == nil && p.isItemsArg(arg) {
+				itemsArg = &args[i]
+				continue
+			}
+			rest = append(rest, arg)
+		}
+		if itemsArg == nil {
+			if p.Placeholder != "" {
+				return nil, nil, fmt.Errorf("missing argument for binding %q", p.Placeholder)
+			}
+			if len(args) > 0 {
+				return nil, nil, fmt.Errorf("unexpected named argument %q", args[0].Name)
+			}
+			return nil, nil, errors.New("missing argument for the items to insert")
+		}
+		var err error
+		values, err = argToListOfMaps(itemsArg.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+		args = rest
+	}
+	if p.Condition == nil && p.UpsertSet == nil && p.Templates == nil && len(args) > 0 {
+		if len(p.Values) > 0 {
+			return nil, nil, errors.New("no arguments expected")
+		}
+		return nil, nil, errors.New("too many arguments")
+	}
+	if len(values) == 0 {
+		return nil, nil, errors.New("no values to insert")
+	}
+	if p.Templates != nil {
+		return p.templateItems(values, args)
+	}
+	condValues, err := bindArgs(p.FixedParams, p.NamedParams, p.PositionalParams, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return values, usedValues(condValues, p.Condition, p.UpsertSet), nil
+}
+
+// templateItems returns the items in VALUES, with the placeholders in the templates bound to the args, and the values
+// of the args bound in the other clauses.
+func (p *PreparedInsert) templateItems(values []map[string]*dynamodb.AttributeValue, args []driver.NamedValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, error) {
+	bound, err := p.bindDocumentArgs(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	items := make([]map[string]*dynamodb.AttributeValue, 0, len(values))
+	for i, item := range values {
+		if template := p.Templates[i]; template != nil {
+			av, err := bindJSON(template, bound)
+			if err != nil {
+				return nil, nil, err
+			}
+			item = av.M
+		}
+		items = append(items, item)
+	}
+	return items, usedValues(bound, p.Condition, p.UpsertSet), nil
+}
+
+// rowItems returns an item for each row of an INSERT with a column list, and the values of the args bound in the
+// other clauses.
+func (p *PreparedInsert) rowItems(args []driver.NamedValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, error) {
+	bound, err := p.bindDocumentArgs(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	items := make([]map[string]*dynamodb.AttributeValue, 0, len(p.Rows))
+	for _, row := range p.Rows {
+		item := make(map[string]*dynamodb.AttributeValue, len(p.Columns))
+		for i, column := range p.Columns {
+			av, err := bindJSON(row[i], bound)
+			if err != nil {
+				return nil, nil, err
+			}
+			item[column] = av
+		}
+		items = append(items, item)
+	}
+	return items, usedValues(bound, p.Condition, p.UpsertSet), nil
+}
+
+// bindDocumentArgs binds the args of a statement whose items contain placeholders. Args that toAttributeValue does
+// not convert, such as ints, slices and structs, are marshaled with dynamodbattribute, as the items of a document
+// INSERT are.
+func (p *PreparedInsert) bindDocumentArgs(args []driver.NamedValue) (map[string]*dynamodb.AttributeValue, error) {
+	converted := make([]driver.NamedValue, 0, len(args))
+	for _, arg := range args {
+		av, err := toAttributeValue(arg.Value)
+		if err != nil {
+			av, err = dynamodbattribute.Marshal(arg.Value)
+			if err != nil {
+				return nil, err
+			}
+		}
+		arg.Value = av
+		converted = append(converted, arg)
+	}
+	return bindArgs(p.FixedParams, p.NamedParams, p.PositionalParams, converted)
+}
+
+// isItemsArg returns true if the items are bound to the arg. A positional placeholder for the items comes before
+// any in WHERE, so it is bound to the first positional arg.
+func (p *PreparedInsert) isItemsArg(arg driver.NamedValue) bool {
+	if p.Placeholder == "" {
+		return arg.Name == ""
+	}
+	return ":"+arg.Name == p.Placeholder
+}
+
+func (p *PreparedInsert) toTransactWrite(items []map[string]*dynamodb.AttributeValue, condValues map[string]*dynamodb.AttributeValue) *dynamodb.TransactWriteItemsInput {
 	conditionExpr, exprAttrNames := p.conditionExpr()
 
 	puts := make([]*dynamodb.TransactWriteItem, 0, len(items))
 	for _, item := range items {
 		puts = append(puts, &dynamodb.TransactWriteItem{
 			Put: &dynamodb.Put{
-				ConditionExpression:      conditionExpr,
-				ExpressionAttributeNames: exprAttrNames,
-				Item:                     item,
-				TableName:                &p.Table.Name,
+				ConditionExpression:       conditionExpr,
+				ExpressionAttributeNames:  exprAttrNames,
+				ExpressionAttributeValues: condValues,
+				Item:                      item,
+				TableName:                 &p.Table.Name,
 			},
 		})
 	}
@@ -162,21 +849,23 @@ func (p *PreparedInsert) toTransactWrite(items []map[string]*dynamodb.AttributeV
 	}
 }
 
-func (p *PreparedInsert) toPutItem(item map[string]*dynamodb.AttributeValue) *dynamodb.PutItemInput {
+func (p *PreparedInsert) toPutItem(item map[string]*dynamodb.AttributeValue, condValues map[string]*dynamodb.AttributeValue) *dynamodb.PutItemInput {
 	conditionExpr, exprAttrNames := p.conditionExpr()
 	return &dynamodb.PutItemInput{
-		ConditionExpression:      conditionExpr,
-		ExpressionAttributeNames: exprAttrNames,
-		Item:                     item,
-		TableName:                &p.Table.Name,
-		ReturnValues:             p.Returning,
+		ConditionExpression:       conditionExpr,
+		ExpressionAttributeNames:  exprAttrNames,
+		ExpressionAttributeValues: condValues,
+		Item:                      item,
+		TableName:                 &p.Table.Name,
+		ReturnValues:              p.Returning,
 	}
 }
 
-// In REPLACE, return nil. In INSERT, return a condition that will fail Puts on existing rows.
+// In REPLACE, return the condition from WHERE, if any. In INSERT, return a condition that will fail Puts on existing
+// rows.
 func (p *PreparedInsert) conditionExpr() (conditionExpr *string, exprAttrNames map[string]*string) {
 	if p.Replace {
-		return nil, nil
+		return p.Condition, p.ConditionNames
 	}
 	ctx := Context{}
 	key := ctx.substitute(p.Table.HashKey)
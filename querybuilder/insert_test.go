@@ -0,0 +1,495 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// failedPut fails every PutItem with a failed condition.
+type failedPut struct {
+	fixtureTables
+	req *dynamodb.PutItemInput
+}
+
+func (d *failedPut) PutItem(req *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	d.req = req
+	return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+}
+
+func (d *failedPut) PutItemWithContext(ctx aws.Context, req *dynamodb.PutItemInput, opts ...request.Option) (*dynamodb.PutItemOutput, error) {
+	return d.PutItem(req)
+}
+
+func TestReplaceWhere(t *testing.T) {
+	prepareInsert := func(t *testing.T, query string) (*PreparedInsert, error) {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		return PrepareInsert(context.Background(), schema.NewTableLoader(&fixtureTables{}), ast)
+	}
+	item := map[string]*dynamodb.AttributeValue{
+		"title":   {S: aws.String("Big")},
+		"year":    {N: aws.String("1988")},
+		"version": {N: aws.String("2")},
+	}
+	doc := `{"title": "Big", "year": 1988, "version": 2}`
+
+	t.Run("named", func(t *testing.T) {
+		p, err := prepareInsert(t, `REPLACE INTO movies VALUES (:doc) WHERE (version = :expected OR attribute_not_exists(title))`)
+		require.NoError(t, err)
+		items, condValues, err := p.items([]driver.NamedValue{
+			{Name: "expected", Ordinal: 1, Value: int64(1)},
+			{Name: "doc", Ordinal: 2, Value: doc},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &dynamodb.PutItemInput{
+			TableName:           aws.String("movies"),
+			Item:                item,
+			ConditionExpression: aws.String("(version = :expected OR attribute_not_exists(title))"),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":expected": {N: aws.String("1")},
+			},
+		}, p.toPutItem(items[0], condValues))
+	})
+
+	t.Run("positional", func(t *testing.T) {
+		p, err := prepareInsert(t, `REPLACE INTO movies VALUES (?) WHERE version = ?`)
+		require.NoError(t, err)
+		items, condValues, err := p.items([]driver.NamedValue{
+			{Ordinal: 1, Value: []string{doc, doc}},
+			{Ordinal: 2, Value: int64(1)},
+		})
+		require.NoError(t, err)
+		req := p.toTransactWrite(items, condValues)
+		require.Len(t, req.TransactItems, 2)
+		for _, write := range req.TransactItems {
+			require.Equal(t, "version = :_pos2", *write.Put.ConditionExpression)
+			require.Equal(t, map[string]*dynamodb.AttributeValue{":_pos2": {N: aws.String("1")}}, write.Put.ExpressionAttributeValues)
+		}
+	})
+
+	t.Run("condition failed", func(t *testing.T) {
+		p, err := prepareInsert(t, `REPLACE INTO movies VALUES ('`+doc+`') WHERE version = 1`)
+		require.NoError(t, err)
+		dynamo := &failedPut{}
+		_, err = p.Do(context.Background(), dynamo, nil)
+		require.True(t, errors.Is(err, ErrConditionFailed))
+		require.True(t, isConditionalCheckFailed(err))
+		require.Equal(t, "version = :_gen1", *dynamo.req.ConditionExpression)
+
+		// Without WHERE, the error is returned as is
+		p, err = prepareInsert(t, `INSERT INTO movies VALUES ('`+doc+`')`)
+		require.NoError(t, err)
+		_, err = p.Do(context.Background(), dynamo, nil)
+		require.False(t, errors.Is(err, ErrConditionFailed))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := prepareInsert(t, `INSERT INTO movies VALUES (?) WHERE version = ?`)
+		require.EqualError(t, err, "WHERE is only allowed on REPLACE, since INSERT only writes items that do not exist")
+		_, err = prepareInsert(t, `REPLACE INTO movies VALUES (?) WHERE version = ? NOT ATOMIC`)
+		require.EqualError(t, err, "WHERE is not allowed with NOT ATOMIC, since BatchWriteItem cannot check conditions")
+		_, err = prepareInsert(t, `REPLACE INTO movies VALUES (?) WHERE version = :version`)
+		require.EqualError(t, err, "cannot mix positional params (?) with named params (:param)")
+		_, err = prepareInsert(t, `REPLACE INTO movies VALUES (:doc) WHERE version = :doc`)
+		require.EqualError(t, err, ":doc is bound to the items, and cannot be used in WHERE")
+	})
+}
+
+// conflictingTransact cancels every transaction containing a Put of an item whose title exists, as DynamoDB does when the
+// attribute_not_exists condition fails, and fails a single PutItem of such an item. Updates are recorded.
+type conflictingTransact struct {
+	fixtureTables
+	titles   map[string]bool
+	attempts int
+	written  []string
+	updates  []*dynamodb.UpdateItemInput
+}
+
+func (d *conflictingTransact) PutItemWithContext(ctx aws.Context, req *dynamodb.PutItemInput, opts ...request.Option) (*dynamodb.PutItemOutput, error) {
+	d.attempts++
+	if d.titles[*req.Item["title"].S] {
+		return nil, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+	}
+	d.written = append(d.written, *req.Item["title"].S)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (d *conflictingTransact) UpdateItemWithContext(ctx aws.Context, req *dynamodb.UpdateItemInput, opts ...request.Option) (*dynamodb.UpdateItemOutput, error) {
+	d.updates = append(d.updates, req)
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (d *conflictingTransact) TransactWriteItemsWithContext(ctx aws.Context, req *dynamodb.TransactWriteItemsInput, opts ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+	var reasons []*dynamodb.CancellationReason
+	canceled := false
+	for _, write := range req.TransactItems {
+		if write.Update != nil {
+			d.updates = append(d.updates, toUpdateItem(write.Update))
+			continue
+		}
+		code := "None"
+		if d.titles[*write.Put.Item["title"].S] {
+			code = "ConditionalCheckFailed"
+			canceled = true
+		}
+		reasons = append(reasons, &dynamodb.CancellationReason{Code: aws.String(code)})
+	}
+	if reasons == nil {
+		return &dynamodb.TransactWriteItemsOutput{}, nil
+	}
+	d.attempts++
+	if canceled {
+		return nil, &dynamodb.TransactionCanceledException{CancellationReasons: reasons}
+	}
+	for _, write := range req.TransactItems {
+		d.written = append(d.written, *write.Put.Item["title"].S)
+	}
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+func TestInsertOnConflict(t *testing.T) {
+	prepareInsert := func(t *testing.T, query string) (*PreparedInsert, error) {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		return PrepareInsert(context.Background(), schema.NewTableLoader(&fixtureTables{}), ast)
+	}
+
+	t.Run("do nothing", func(t *testing.T) {
+		p, err := prepareInsert(t, `INSERT INTO movies VALUES (?) ON CONFLICT DO NOTHING`)
+		require.NoError(t, err)
+		dynamo := &conflictingTransact{titles: map[string]bool{"Big": true, "Up": true}}
+		result, err := p.Do(context.Background(), dynamo, []driver.NamedValue{{Ordinal: 1, Value: []string{
+			`{"title": "Big", "year": 1988}`,
+			`{"title": "Heat", "year": 1995}`,
+			`{"title": "Up", "year": 2009}`,
+			`{"title": "Jaws", "year": 1975}`,
+		}}})
+		require.NoError(t, err)
+		require.Equal(t, 2, dynamo.attempts)
+		require.Equal(t, []string{"Heat", "Jaws"}, dynamo.written)
+		count, err := result.RowsAffected()
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+	})
+
+	t.Run("do nothing with every item existing", func(t *testing.T) {
+		p, err := prepareInsert(t, `INSERT INTO movies VALUES ({"title": "Big", "year": 1988}), ({"title": "Up", "year": 2009}) ON CONFLICT DO NOTHING`)
+		require.NoError(t, err)
+		dynamo := &conflictingTransact{titles: map[string]bool{"Big": true, "Up": true}}
+		result, err := p.Do(context.Background(), dynamo, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, dynamo.attempts)
+		require.Empty(t, dynamo.written)
+		count, err := result.RowsAffected()
+		require.NoError(t, err)
+		require.Equal(t, int64(0), count)
+	})
+
+	t.Run("do nothing with a single existing item", func(t *testing.T) {
+		p, err := prepareInsert(t, `INSERT INTO movies VALUES ('{"title": "Big", "year": 1988}') ON CONFLICT DO NOTHING`)
+		require.NoError(t, err)
+		result, err := p.Do(context.Background(), &failedPut{}, nil)
+		require.NoError(t, err)
+		count, err := result.RowsAffected()
+		require.NoError(t, err)
+		require.Equal(t, int64(0), count)
+	})
+
+	t.Run("do update", func(t *testing.T) {
+		p, err := prepareInsert(t, `INSERT INTO movies VALUES (:movie) ON CONFLICT DO UPDATE SET views = if_not_exists(views, 0) + :n, info.rating = :rating`)
+		require.NoError(t, err)
+		dynamo := &conflictingTransact{titles: map[string]bool{"Big": true}}
+		result, err := p.Do(context.Background(), dynamo, []driver.NamedValue{
+			{Name: "movie", Value: `{"title": "Big", "year": 1988, "plot": "A boy wakes up big", "views": 10, "info": {"rating": 7}}`},
+			{Name: "n", Value: int64(1)},
+			{Name: "rating", Value: 7.5},
+		})
+		require.NoError(t, err)
+		count, err := result.RowsAffected()
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+		require.Empty(t, dynamo.written)
+		require.Equal(t, []*dynamodb.UpdateItemInput{{
+			TableName: aws.String("movies"),
+			Key: map[string]*dynamodb.AttributeValue{
+				"title": {S: aws.String("Big")},
+				"year":  {N: aws.String("1988")},
+			},
+			UpdateExpression:    aws.String("SET #_item1 = :_item1, #views = if_not_exists(#views, :_gen1) + :n, info.rating = :rating"),
+			ConditionExpression: aws.String("attribute_exists(#_key)"),
+			ExpressionAttributeNames: map[string]*string{
+				"#_item1": aws.String("plot"),
+				"#views":  aws.String("views"),
+				"#_key":   aws.String("title"),
+			},
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":_item1": {S: aws.String("A boy wakes up big")},
+				":_gen1":  {N: aws.String("0")},
+				":n":      {N: aws.String("1")},
+				":rating": {N: aws.String("7.5")},
+			},
+		}}, dynamo.updates)
+	})
+
+	t.Run("do update puts new items", func(t *testing.T) {
+		p, err := prepareInsert(t, `INSERT INTO movies VALUES (?) ON CONFLICT DO UPDATE SET views = views + ?`)
+		require.NoError(t, err)
+		dynamo := &conflictingTransact{titles: map[string]bool{"Big": true, "Up": true}}
+		result, err := p.Do(context.Background(), dynamo, []driver.NamedValue{
+			{Ordinal: 1, Value: []string{
+				`{"title": "Big", "year": 1988}`,
+				`{"title": "Heat", "year": 1995, "views": 5}`,
+				`{"title": "Up", "year": 2009}`,
+			}},
+			{Ordinal: 2, Value: int64(1)},
+		})
+		require.NoError(t, err)
+		count, err := result.RowsAffected()
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count)
+		require.Equal(t, []string{"Heat"}, dynamo.written)
+		require.Len(t, dynamo.updates, 2)
+		require.Equal(t, "Big", *dynamo.updates[0].Key["title"].S)
+		require.Equal(t, "Up", *dynamo.updates[1].Key["title"].S)
+		for _, update := range dynamo.updates {
+			require.Equal(t, "SET #views = #views + :_pos2", *update.UpdateExpression)
+		}
+
+		// A single new item is put without a transaction, and not updated.
+		dynamo = &conflictingTransact{}
+		_, err = p.Do(context.Background(), dynamo, []driver.NamedValue{
+			{Ordinal: 1, Value: `{"title": "Heat", "year": 1995, "views": 5}`},
+			{Ordinal: 2, Value: int64(1)},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"Heat"}, dynamo.written)
+		require.Empty(t, dynamo.updates)
+	})
+
+	t.Run("do update in a transaction", func(t *testing.T) {
+		p, err := prepareInsert(t, `INSERT INTO movies VALUES (?) ON CONFLICT DO UPDATE SET views = views + ?`)
+		require.NoError(t, err)
+		tx, err := NewTransaction()
+		require.NoError(t, err)
+		_, err = p.Transact(tx, []driver.NamedValue{
+			{Ordinal: 1, Value: `{"title": "Big", "year": 1988}`},
+			{Ordinal: 2, Value: int64(1)},
+		})
+		require.EqualError(t, err, "ON CONFLICT DO UPDATE is not allowed in transactions, since whether an item exists is only known once it is written")
+
+		p, err = prepareInsert(t, `INSERT INTO movies VALUES (?) ON CONFLICT DO NOTHING`)
+		require.NoError(t, err)
+		_, err = p.Transact(tx, []driver.NamedValue{{Ordinal: 1, Value: `{"title": "Big", "year": 1988}`}})
+		require.EqualError(t, err, "ON CONFLICT DO NOTHING is not allowed in transactions, since an existing item cancels the transaction")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := prepareInsert(t, `REPLACE INTO movies VALUES (?) ON CONFLICT DO NOTHING`)
+		require.EqualError(t, err, "ON CONFLICT is only allowed on INSERT, since REPLACE overwrites existing items")
+		_, err = prepareInsert(t, `INSERT INTO movies VALUES (?) ON CONFLICT DO UPDATE SET year = 1989`)
+		require.EqualError(t, err, `key attribute "year" may not be updated`)
+		_, err = prepareInsert(t, `INSERT INTO movies VALUES (:movie) ON CONFLICT DO UPDATE SET plot = :movie`)
+		require.EqualError(t, err, ":movie is bound to the items, and cannot be used in ON CONFLICT")
+		_, err = prepareInsert(t, `INSERT INTO movies VALUES (?) ON CONFLICT DO UPDATE SET views = :views`)
+		require.EqualError(t, err, "cannot mix positional params (?) with named params (:param)")
+	})
+}
+
+func TestInsertColumns(t *testing.T) {
+	prepareInsert := func(t *testing.T, query string) (*PreparedInsert, error) {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		return PrepareInsert(context.Background(), schema.NewTableLoader(&fixtureTables{}), ast)
+	}
+
+	t.Run("positional", func(t *testing.T) {
+		p, err := prepareInsert(t, `INSERT INTO movies (title, year, actors) VALUES (?, ?, ?), (?, ?, ?)`)
+		require.NoError(t, err)
+		items, condValues, err := p.items([]driver.NamedValue{
+			{Ordinal: 1, Value: "Big"},
+			{Ordinal: 2, Value: 1988},
+			{Ordinal: 3, Value: []string{"Tom Hanks"}},
+			{Ordinal: 4, Value: "Up"},
+			{Ordinal: 5, Value: int64(2009)},
+			{Ordinal: 6, Value: nil},
+		})
+		require.NoError(t, err)
+		require.Nil(t, condValues)
+		require.Equal(t, []map[string]*dynamodb.AttributeValue{
+			{
+				"title":  {S: aws.String("Big")},
+				"year":   {N: aws.String("1988")},
+				"actors": {L: []*dynamodb.AttributeValue{{S: aws.String("Tom Hanks")}}},
+			},
+			{
+				"title":  {S: aws.String("Up")},
+				"year":   {N: aws.String("2009")},
+				"actors": {NULL: aws.Bool(true)},
+			},
+		}, items)
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		p, err := prepareInsert(t, `INSERT INTO movies (title, year, actors) VALUES (?, ?, ?), (:t2, :y2, :a2)`)
+		require.NoError(t, err)
+		items, condValues, err := p.items([]driver.NamedValue{
+			{Ordinal: 1, Value: "Big"},
+			{Ordinal: 2, Value: 1988},
+			{Ordinal: 3, Value: []string{"Tom Hanks"}},
+			{Name: "t2", Ordinal: 4, Value: "Up"},
+			{Name: "y2", Ordinal: 5, Value: 2009},
+			{Name: "a2", Ordinal: 6, Value: nil},
+		})
+		require.NoError(t, err)
+		require.Nil(t, condValues)
+		require.Equal(t, []map[string]*dynamodb.AttributeValue{
+			{
+				"title":  {S: aws.String("Big")},
+				"year":   {N: aws.String("1988")},
+				"actors": {L: []*dynamodb.AttributeValue{{S: aws.String("Tom Hanks")}}},
+			},
+			{
+				"title":  {S: aws.String("Up")},
+				"year":   {N: aws.String("2009")},
+				"actors": {NULL: aws.Bool(true)},
+			},
+		}, items)
+
+		_, _, err = p.items([]driver.NamedValue{
+			{Ordinal: 1, Value: "Big"},
+			{Ordinal: 2, Value: 1988},
+			{Name: "t2", Ordinal: 3, Value: "Up"},
+			{Name: "y2", Ordinal: 4, Value: 2009},
+			{Name: "a2", Ordinal: 5, Value: nil},
+		})
+		require.EqualError(t, err, "wrong number of arguments, expected 3, got 2")
+	})
+
+	t.Run("named and literals", func(t *testing.T) {
+		p, err := prepareInsert(t, "INSERT INTO movies (title, `year`, info, genres) VALUES (:title, 1988, {\"rating\": 7}, [\"comedy\"]) "+
+			"ON CONFLICT DO UPDATE SET views = if_not_exists(views, :zero) + 1")
+		require.NoError(t, err)
+		items, condValues, err := p.items([]driver.NamedValue{
+			{Name: "title", Ordinal: 1, Value: "Big"},
+			{Name: "zero", Ordinal: 2, Value: 0},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []map[string]*dynamodb.AttributeValue{{
+			"title":  {S: aws.String("Big")},
+			"year":   {N: aws.String("1988")},
+			"info":   {M: map[string]*dynamodb.AttributeValue{"rating": {N: aws.String("7")}}},
+			"genres": {L: []*dynamodb.AttributeValue{{S: aws.String("comedy")}}},
+		}}, items)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{
+			":zero":  {N: aws.String("0")},
+			":_gen1": {N: aws.String("1")},
+		}, condValues)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := prepareInsert(t, `INSERT INTO movies (title, year) VALUES (?, ?), (?)`)
+		require.EqualError(t, err, "row 2 has 1 values, but there are 2 columns")
+		_, err = prepareInsert(t, `INSERT INTO movies (title, year, title) VALUES (?, ?, ?)`)
+		require.EqualError(t, err, `column "title" appears more than once`)
+		_, err = prepareInsert(t, `INSERT INTO movies (title, plot) VALUES (?, ?)`)
+		require.EqualError(t, err, `columns must include the key attribute "year"`)
+
+		p, err := prepareInsert(t, `INSERT INTO movies (title, year) VALUES (:title, :year)`)
+		require.NoError(t, err)
+		_, _, err = p.items([]driver.NamedValue{{Name: "title", Ordinal: 1, Value: "Big"}})
+		require.EqualError(t, err, `missing argument for binding ":year"`)
+	})
+}
+
+func TestInsertTemplates(t *testing.T) {
+	prepareInsert := func(t *testing.T, query string) (*PreparedInsert, error) {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		return PrepareInsert(context.Background(), schema.NewTableLoader(&fixtureTables{}), ast)
+	}
+
+	t.Run("named at any depth", func(t *testing.T) {
+		p, err := prepareInsert(t, `INSERT INTO movies VALUES ({"title": :title, "year": 1988, "info": {"director": :director, "genres": [:g1, :g2]}}), ({"title": "Up", "year": 2009})`)
+		require.NoError(t, err)
+		items, condValues, err := p.items([]driver.NamedValue{
+			{Name: "title", Ordinal: 1, Value: "Big"},
+			{Name: "director", Ordinal: 2, Value: "Penny Marshall"},
+			{Name: "g1", Ordinal: 3, Value: "comedy"},
+			{Name: "g2", Ordinal: 4, Value: []string{"drama", "fantasy"}},
+		})
+		require.NoError(t, err)
+		require.Nil(t, condValues)
+		require.Equal(t, []map[string]*dynamodb.AttributeValue{
+			{
+				"title": {S: aws.String("Big")},
+				"year":  {N: aws.String("1988")},
+				"info": {M: map[string]*dynamodb.AttributeValue{
+					"director": {S: aws.String("Penny Marshall")},
+					"genres": {L: []*dynamodb.AttributeValue{
+						{S: aws.String("comedy")},
+						{L: []*dynamodb.AttributeValue{{S: aws.String("drama")}, {S: aws.String("fantasy")}}},
+					}},
+				}},
+			},
+			{
+				"title": {S: aws.String("Up")},
+				"year":  {N: aws.String("2009")},
+			},
+		}, items)
+	})
+
+	t.Run("positional before WHERE", func(t *testing.T) {
+		p, err := prepareInsert(t, `REPLACE INTO movies VALUES ({"title": ?, "year": ?, "version": ?}) WHERE version = ?`)
+		require.NoError(t, err)
+		items, condValues, err := p.items([]driver.NamedValue{
+			{Ordinal: 1, Value: "Big"},
+			{Ordinal: 2, Value: 1988},
+			{Ordinal: 3, Value: int64(2)},
+			{Ordinal: 4, Value: int64(1)},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []map[string]*dynamodb.AttributeValue{{
+			"title":   {S: aws.String("Big")},
+			"year":    {N: aws.String("1988")},
+			"version": {N: aws.String("2")},
+		}}, items)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{":_pos4": {N: aws.String("1")}}, condValues)
+		require.Equal(t, "version = :_pos4", *p.Condition)
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		p, err := prepareInsert(t, `REPLACE INTO movies VALUES ({"title": ?, "year": ?}) WHERE version = :version`)
+		require.NoError(t, err)
+		items, condValues, err := p.items([]driver.NamedValue{
+			{Ordinal: 1, Value: "Big"},
+			{Name: "version", Ordinal: 2, Value: int64(1)},
+			{Ordinal: 3, Value: 1988},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []map[string]*dynamodb.AttributeValue{{
+			"title": {S: aws.String("Big")},
+			"year":  {N: aws.String("1988")},
+		}}, items)
+		require.Equal(t, map[string]*dynamodb.AttributeValue{":version": {N: aws.String("1")}}, condValues)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		p, err := prepareInsert(t, `INSERT INTO movies VALUES ({"title": :title, "year": :year})`)
+		require.NoError(t, err)
+		_, _, err = p.items([]driver.NamedValue{{Name: "title", Ordinal: 1, Value: "Big"}})
+		require.EqualError(t, err, `missing argument for binding ":year"`)
+		_, _, err = p.items([]driver.NamedValue{{Ordinal: 1, Value: "Big"}, {Ordinal: 2, Value: int64(1988)}})
+		require.Error(t, err)
+	})
+}
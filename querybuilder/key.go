@@ -0,0 +1,117 @@
+package querybuilder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+type errPrimaryKey struct {
+	hashKey string
+	sortKey string
+}
+
+func (e errPrimaryKey) Error() string {
+	if e.sortKey == "" {
+		return fmt.Sprintf("partition key must appear in the WHERE clause in an equality condition, such as: WHERE %s = :param", e.hashKey)
+	}
+	return fmt.Sprintf("partition key and sort key must appear in the WHERE clause in equality conditions, such as: WHERE %s = :param1 AND %s = :param2", e.hashKey, e.sortKey)
+}
+
+// extractPrimaryKey splits the top-level terms of a WHERE clause into the equality conditions that select a single
+// item by its primary key, and the remaining terms. The returned key maps key attribute names to the placeholders
+// holding their values, so prepareValuesAndPlaceholders must have been called on the expression first.
+func extractPrimaryKey(ctx *Context, expr *parser.AndExpression) (key map[string]string, cond *parser.AndExpression, err error) {
+	key = make(map[string]string, 2)
+	cond = &parser.AndExpression{}
+	if expr != nil {
+		for _, term := range expr.And {
+			if name, placeholder := keyEquality(ctx, term); name != "" {
+				if _, ok := key[name]; !ok {
+					key[name] = placeholder
+					continue
+				}
+			}
+			cond.And = append(cond.And, term)
+		}
+	}
+	if _, ok := key[ctx.HashKey]; !ok {
+		return nil, nil, errPrimaryKey{ctx.HashKey, ctx.SortKey}
+	}
+	if _, ok := key[ctx.SortKey]; ctx.SortKey != "" && !ok {
+		return nil, nil, errPrimaryKey{ctx.HashKey, ctx.SortKey}
+	}
+	return key, cond, nil
+}
+
+// keyEquality returns the key attribute name and value placeholder if the condition is of the form key = :value.
+func keyEquality(ctx *Context, term *parser.Condition) (name, placeholder string) {
+	if term.Operand == nil || term.Operand.ConditionRHS.Compare == nil {
+		return "", ""
+	}
+	cmp := term.Operand.ConditionRHS.Compare
+	if cmp.Operator != "=" || cmp.Operand.Value == nil || cmp.Operand.Value.PlaceHolder == nil {
+		return "", ""
+	}
+	name = term.Operand.Operand.String()
+	if !ctx.IsKey(name) {
+		return "", ""
+	}
+	return name, *cmp.Operand.Value.PlaceHolder
+}
+
+// bindKey builds the primary key of an item from the bound values.
+func bindKey(key map[string]string, values map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue {
+	out := make(map[string]*dynamodb.AttributeValue, len(key))
+	for name, placeholder := range key {
+		out[name] = values[placeholder]
+	}
+	return out
+}
+
+// keyString returns a string that uniquely identifies a bound primary key, such as: title="Big", year=1988
+func keyString(key map[string]*dynamodb.AttributeValue) string {
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		av := key[name]
+		var value string
+		switch {
+		case av.S != nil:
+			value = strconv.Quote(*av.S)
+		case av.N != nil:
+			value = *av.N
+			// Numbers are compared by value, so 1988 and 1988.0 are the same key. 128 bits tell apart the up to 38
+			// digits of a DynamoDB number.
+			if n, ok := new(big.Float).SetPrec(128).SetString(value); ok {
+				value = n.Text('g', -1)
+			}
+		case av.B != nil:
+			value = "0x" + hex.EncodeToString(av.B)
+		default:
+			value = strings.Join(strings.Fields(av.String()), " ")
+		}
+		parts[i] = name + "=" + value
+	}
+	return strings.Join(parts, ", ")
+}
+
+// requireExists prepends a condition that fails if the item does not exist to the condition expression.
+func requireExists(ctx *Context, conditionExpr string) string {
+	exists := fmt.Sprintf("attribute_exists(%s)", ctx.substitute(ctx.HashKey))
+	if conditionExpr == "" {
+		return exists
+	}
+	return exists + " AND " + conditionExpr
+}
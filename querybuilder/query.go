@@ -19,13 +19,26 @@ import (
 	"github.com/mightyguava/dynamosql/schema"
 )
 
+// maxSegments is the maximum number of segments in a parallel scan. DynamoDB allows up to 1000000, but each segment
+// makes at least one request, and only a few segments are scanned at once.
+const maxSegments = 1000
+
 var (
 	errPositionalArg = errors.New("unexpected positional arg, use sql.NamedArg to pass named arguments")
 	errNamedArg      = errors.New("unexpected named arg, to use named args, provided named placeholders like :param")
 )
 
 type PreparedQuery struct {
-	Query            *dynamodb.QueryInput
+	Query *dynamodb.QueryInput
+	// Set instead of Query when the partition key is matched against multiple values using IN or OR.
+	Partitions []*PartitionQuery
+	// Set instead of Query when the WHERE clause matches a single item by its full primary key.
+	GetItem *dynamodb.GetItemInput
+	// Set instead of Query when the WHERE clause matches multiple items by their full primary keys.
+	BatchGet *dynamodb.BatchGetItemInput
+	// The primary keys of the items to get, mapping key attribute names to placeholders.
+	Keys             []map[string]string
+	Scan             *dynamodb.ScanInput
 	Limit            int
 	Columns          []*parser.ProjectionColumn
 	NamedParams      NamedParams
@@ -33,11 +46,25 @@ type PreparedQuery struct {
 	FixedParams      map[string]interface{}
 }
 
+// PartitionQuery is a Query that is made once for each value the partition key is matched against.
+type PartitionQuery struct {
+	Query *dynamodb.QueryInput
+	// The placeholder for the partition key value in the KeyConditionExpression.
+	HashKeyParam string
+	// The placeholders of the partition key values to query.
+	HashKeyValues []string
+}
+
 func PrepareQuery(ctx context.Context, tables *schema.TableLoader, query string) (*PreparedQuery, error) {
 	ast, err := parser.Parse(query)
 	if err != nil {
 		return nil, err
 	}
+	return PrepareQueryAST(ctx, tables, ast)
+}
+
+// PrepareQueryAST is like PrepareQuery, but prepares a statement that has already been parsed.
+func PrepareQueryAST(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) (*PreparedQuery, error) {
 	sel := ast.Select
 	if sel == nil {
 		return nil, fmt.Errorf("expected SELECT but got %s", repr.String(ast))
@@ -59,6 +86,79 @@ func (pq *PreparedQuery) NewRequest(args []driver.NamedValue) (*dynamodb.QueryIn
 	return &req, nil
 }
 
+// NewRequests returns a request for each distinct partition key value matched by the query. If the partition key is
+// matched against a single value, a single request is returned.
+func (pq *PreparedQuery) NewRequests(args []driver.NamedValue) ([]*dynamodb.QueryInput, error) {
+	if pq.Query != nil {
+		req, err := pq.NewRequest(args)
+		if err != nil {
+			return nil, err
+		}
+		return []*dynamodb.QueryInput{req}, nil
+	}
+	values, err := bindArgs(pq.FixedParams, pq.NamedParams, pq.PositionalParams, args)
+	if err != nil {
+		return nil, err
+	}
+	var reqs []*dynamodb.QueryInput
+	seen := make(map[string]bool)
+	for _, partition := range pq.Partitions {
+		queried := make(map[string]bool, len(partition.HashKeyValues))
+		for _, param := range partition.HashKeyValues {
+			value := values[param]
+			key := value.String()
+			if queried[key] {
+				continue
+			}
+			if seen[key] {
+				// The conditions of both branches would have to be merged into one Query, which is not always
+				// possible since key conditions do not support OR.
+				return nil, errors.New("each partition key value may only be matched by one OR condition")
+			}
+			queried[key] = true
+			req := *partition.Query
+			values[partition.HashKeyParam] = value
+			req.ExpressionAttributeValues = usedValues(values, req.KeyConditionExpression, req.FilterExpression)
+			reqs = append(reqs, &req)
+		}
+		for key := range queried {
+			seen[key] = true
+		}
+	}
+	return reqs, nil
+}
+
+func (pq *PreparedQuery) NewScanRequest(args []driver.NamedValue) (*dynamodb.ScanInput, error) {
+	values, err := bindArgs(pq.FixedParams, pq.NamedParams, pq.PositionalParams, args)
+	if err != nil {
+		return nil, err
+	}
+	req := *pq.Scan
+	if len(values) > 0 {
+		req.ExpressionAttributeValues = values
+	}
+	return &req, nil
+}
+
+// NewScanRequests returns a request for each segment of a parallel scan. If the SCAN is not segmented, a single
+// request is returned.
+func (pq *PreparedQuery) NewScanRequests(args []driver.NamedValue) ([]*dynamodb.ScanInput, error) {
+	req, err := pq.NewScanRequest(args)
+	if err != nil {
+		return nil, err
+	}
+	if req.TotalSegments == nil {
+		return []*dynamodb.ScanInput{req}, nil
+	}
+	reqs := make([]*dynamodb.ScanInput, *req.TotalSegments)
+	for i := range reqs {
+		segment := *req
+		segment.Segment = aws.Int64(int64(i))
+		reqs[i] = &segment
+	}
+	return reqs, nil
+}
+
 func bindArgs(fixedParams map[string]interface{}, namedParams NamedParams, positionalParams map[int]string, args []driver.NamedValue) (map[string]*dynamodb.AttributeValue, error) {
 	values := make(map[string]*dynamodb.AttributeValue, len(namedParams)+len(fixedParams))
 
@@ -71,7 +171,32 @@ func bindArgs(fixedParams map[string]interface{}, namedParams NamedParams, posit
 		values[k] = av
 	}
 
-	if len(namedParams) > 0 {
+	if len(namedParams) > 0 && len(positionalParams) > 0 {
+		// Only the rows of an INSERT may mix params. Named args are bound by name, and the others by their order.
+		var named, positional []driver.NamedValue
+		for _, arg := range args {
+			if arg.Name != "" {
+				named = append(named, arg)
+				continue
+			}
+			arg.Ordinal = len(positional) + 1
+			positional = append(positional, arg)
+		}
+		namedValues, err := bindArgs(nil, namedParams, nil, named)
+		if err != nil {
+			return nil, err
+		}
+		positionalValues, err := bindArgs(nil, nil, positionalParams, positional)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range namedValues {
+			values[k] = v
+		}
+		for k, v := range positionalValues {
+			values[k] = v
+		}
+	} else if len(namedParams) > 0 {
 		// Bind named params using the args
 		namedParams = namedParams.Clone()
 		for _, arg := range args {
@@ -117,6 +242,46 @@ func bindArgs(fixedParams map[string]interface{}, namedParams NamedParams, posit
 	return values, nil
 }
 
+// usedValues returns the subset of values whose placeholders are referenced by the expressions. DynamoDB rejects
+// requests containing unused expression attribute values.
+func usedValues(values map[string]*dynamodb.AttributeValue, exprs ...*string) map[string]*dynamodb.AttributeValue {
+	used := make(map[string]*dynamodb.AttributeValue, len(values))
+	for _, expr := range exprs {
+		if expr == nil {
+			continue
+		}
+		for _, placeholder := range placeholderRegexp.FindAllString(*expr, -1) {
+			if v, ok := values[placeholder]; ok {
+				used[placeholder] = v
+			}
+		}
+	}
+	if len(used) == 0 {
+		return nil
+	}
+	return used
+}
+
+// usedNames returns the subset of names whose substitutions are referenced by the expressions. DynamoDB rejects
+// requests containing unused expression attribute names.
+func usedNames(names map[string]*string, exprs ...*string) map[string]*string {
+	used := make(map[string]*string, len(names))
+	for _, expr := range exprs {
+		if expr == nil {
+			continue
+		}
+		for _, sub := range substitutionRegexp.FindAllString(*expr, -1) {
+			if v, ok := names[sub]; ok {
+				used[sub] = v
+			}
+		}
+	}
+	if len(used) == 0 {
+		return nil
+	}
+	return used
+}
+
 func toAttributeValue(attr interface{}) (*dynamodb.AttributeValue, error) {
 	switch v := attr.(type) {
 	case *dynamodb.AttributeValue:
@@ -159,59 +324,180 @@ func prepare(table *schema.Table, ast *parser.Select) (*PreparedQuery, error) {
 	}
 	ctx := NewContext(table, index)
 	visit := &visitor{Context: ctx}
-	if err := prepareValuesAndPlaceholders(ctx, ast.Where); err != nil {
-		return nil, err
+	// Each top-level OR branch of the WHERE clause
+	branches := append([]*parser.AndExpression{ast.Where}, ast.Or...)
+	for _, branch := range branches {
+		if err := prepareValuesAndPlaceholders(ctx, branch); err != nil {
+			return nil, err
+		}
 	}
-	kf := extractKeyExpressions(ast.Where, ctx.IsKey)
-	keyExpr, err := buildKeyExpression(ctx, kf.Key)
-	if err != nil {
-		return nil, err
+	if ast.Segments != nil && (!ast.Scan || *ast.Segments < 1 || *ast.Segments > maxSegments) {
+		return nil, fmt.Errorf("SEGMENTS may only be used with SCAN, and must be between 1 and %d", maxSegments)
 	}
-	filterExpr, err := buildFilterExpression(ctx, kf.Filter)
-	if err != nil {
-		return nil, err
+	if !ast.Scan && index == "" && len(ast.Or) == 0 {
+		keys, batch, err := extractItemKeys(ctx, ast.Where)
+		if err != nil {
+			return nil, err
+		}
+		if keys != nil {
+			return prepareGet(ctx, ast, keys, batch)
+		}
 	}
-	var projectionExpr *string
-	if !ast.Projection.All {
-		expr, err := buildProjectionExpression(ctx, ast.Projection)
+	var scanFilterExpr string
+	var conds []*queryCondition
+	filtered := false
+	if ast.Scan {
+		if ast.Descending != nil {
+			return nil, errors.New("SCAN results are unordered, ASC and DESC are not allowed")
+		}
+		// Scan filters may reference key attributes.
+		var err error
+		scanFilterExpr, err = (&visitor{Context: ctx, allowKeys: true}).VisitFilterExpression(&parser.ConditionExpression{Or: branches})
 		if err != nil {
 			return nil, err
 		}
-		projectionExpr = aws.String(expr)
+		filtered = scanFilterExpr != ""
+	} else {
+		for _, branch := range branches {
+			cond, err := buildQueryCondition(ctx, branch)
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, cond)
+			filtered = filtered || cond.filterExpr != ""
+		}
+	}
+	projectionExpr, err := buildOptionalProjectionExpression(ctx, ast.Projection)
+	if err != nil {
+		return nil, err
 	}
 	if len(ctx.PositionalParams) > 0 && len(ctx.NamedParams) > 0 {
 		return nil, errors.New("cannot mix positional params (?) with named params (:param)")
 	}
 
 	req := &dynamodb.QueryInput{
-		TableName:              &ast.From,
-		ProjectionExpression:   projectionExpr,
-		KeyConditionExpression: aws.String(keyExpr),
-	}
-	if filterExpr != "" {
-		req.FilterExpression = aws.String(filterExpr)
+		TableName:            &ast.From,
+		ProjectionExpression: projectionExpr,
 	}
 	req.ExpressionAttributeNames = ctx.ExpressionAttributeNames()
 	if index != "" {
 		req.IndexName = aws.String(index)
 	}
 	limit := 0
-	if ast.Limit != nil && filterExpr == "" {
+	if ast.Limit != nil && !filtered {
 		// Only apply the limit if there is no filter, since DynamoDB applies limits BEFORE the filter.
 		req.Limit = aws.Int64(int64(*ast.Limit))
 		limit = *ast.Limit
 	}
-	if ast.Descending != nil {
-		req.ScanIndexForward = aws.Bool(!bool(*ast.Descending))
-	}
-	return &PreparedQuery{
-		Query:            req,
+	prepared := &PreparedQuery{
 		Limit:            limit,
 		Columns:          ast.Projection.Columns,
 		NamedParams:      visit.Context.NamedParams,
 		PositionalParams: visit.Context.PositionalParams,
 		FixedParams:      visit.Context.FixedParams,
-	}, nil
+	}
+	if ast.Scan {
+		prepared.Scan = &dynamodb.ScanInput{
+			TableName:                req.TableName,
+			IndexName:                req.IndexName,
+			ProjectionExpression:     req.ProjectionExpression,
+			FilterExpression:         optionalString(scanFilterExpr),
+			ExpressionAttributeNames: req.ExpressionAttributeNames,
+			Limit:                    req.Limit,
+		}
+		if ast.Segments != nil {
+			prepared.Scan.TotalSegments = aws.Int64(int64(*ast.Segments))
+		}
+		return prepared, nil
+	}
+	if ast.Descending != nil {
+		req.ScanIndexForward = aws.Bool(!bool(*ast.Descending))
+	}
+	if len(conds) == 1 && !conds[0].in {
+		req.KeyConditionExpression = aws.String(conds[0].keyExpr)
+		req.FilterExpression = optionalString(conds[0].filterExpr)
+		prepared.Query = req
+		return prepared, nil
+	}
+	// Fan out to a Query for each partition key value.
+	for _, cond := range conds {
+		partition := *req
+		partition.KeyConditionExpression = aws.String(cond.keyExpr)
+		partition.FilterExpression = optionalString(cond.filterExpr)
+		partition.ExpressionAttributeNames = usedNames(req.ExpressionAttributeNames,
+			partition.KeyConditionExpression, partition.FilterExpression, partition.ProjectionExpression)
+		prepared.Partitions = append(prepared.Partitions, &PartitionQuery{
+			Query:         &partition,
+			HashKeyParam:  cond.hashKeyParam,
+			HashKeyValues: cond.hashKeyValues,
+		})
+	}
+	return prepared, nil
+}
+
+// queryCondition is the key condition and filter of a Query, built from one top-level OR branch of the WHERE clause.
+type queryCondition struct {
+	keyExpr    string
+	filterExpr string
+	// The placeholder for the partition key value in keyExpr.
+	hashKeyParam string
+	// The placeholders of the partition key values to query. If the partition key is matched with IN, there may be
+	// multiple.
+	hashKeyValues []string
+	in            bool
+}
+
+func buildQueryCondition(ctx *Context, expr *parser.AndExpression) (*queryCondition, error) {
+	cond := &queryCondition{}
+	if expr != nil {
+		for _, term := range expr.And {
+			if term.TupleIn != nil {
+				return nil, errTupleIn
+			}
+		}
+	}
+	kf := extractKeyExpressions(expr, ctx.IsKey)
+	for _, term := range kf.Key.And {
+		if term.Operand == nil || term.Operand.Operand.String() != ctx.HashKey || cond.hashKeyParam != "" {
+			continue
+		}
+		if in := term.Operand.ConditionRHS.In; in != nil {
+			// Query once for each value in the list, by rewriting "key IN (...)" to "key = :param" and binding
+			// :param to each value in turn.
+			for _, value := range in.Values {
+				cond.hashKeyValues = append(cond.hashKeyValues, *value.PlaceHolder)
+			}
+			cond.hashKeyParam = ctx.NextGeneratedParam()
+			cond.in = true
+			term.Operand.ConditionRHS = &parser.ConditionRHS{Compare: &parser.Compare{
+				Operator: "=",
+				Operand:  &parser.Operand{Value: &parser.Value{PlaceHolder: &cond.hashKeyParam}},
+			}}
+		} else if name, placeholder := keyEquality(ctx, term); name == ctx.HashKey {
+			cond.hashKeyParam = placeholder
+			cond.hashKeyValues = []string{placeholder}
+		}
+	}
+	var err error
+	cond.keyExpr, err = buildKeyExpression(ctx, kf.Key)
+	if err != nil {
+		return nil, err
+	}
+	if cond.hashKeyParam == "" {
+		return nil, errHashKey(ctx.HashKey)
+	}
+	cond.filterExpr, err = buildFilterExpression(ctx, kf.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return cond, nil
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
 }
 
 // Context tracks expression state as DynamoDB request is built.
@@ -304,11 +590,10 @@ func (c *Context) substitute(symbol string) string {
 	return symbol
 }
 
-func prepareValuesAndPlaceholders(ctx *Context, expr *parser.AndExpression) error {
-	if expr == nil {
-		return nil
-	}
-	return parser.Visit(expr, func(node parser.Node, next func() error) error {
+// prepareValuesAndPlaceholders replaces literal values and positional placeholders in the node with named
+// placeholders, recording their values in the Context.
+func prepareValuesAndPlaceholders(ctx *Context, node parser.Node) error {
+	return parser.Visit(node, func(node parser.Node, next func() error) error {
 		if node, ok := node.(*parser.Value); ok {
 			var replace parser.Value
 			switch {
@@ -347,6 +632,12 @@ func prepareValuesAndPlaceholders(ctx *Context, expr *parser.AndExpression) erro
 // matches an identifier that is valid for use in an expression
 var validIdentifierRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
 
+// matches a value placeholder within an expression
+var placeholderRegexp = regexp.MustCompile(`:[a-zA-Z0-9_]+`)
+
+// matches an attribute name substitution within an expression
+var substitutionRegexp = regexp.MustCompile(`#[a-zA-Z0-9_]+`)
+
 type keyAndFilter struct {
 	Key    *parser.AndExpression
 	Filter *parser.AndExpression
@@ -425,6 +716,13 @@ func buildFilterExpression(ctx *Context, filter *parser.AndExpression) (string,
 	return v.VisitFilterExpression(filter)
 }
 
+// buildConditionExpression builds a ConditionExpression for a write. Unlike filter expressions, conditions may
+// reference key attributes.
+func buildConditionExpression(ctx *Context, cond *parser.AndExpression) (string, error) {
+	v := &visitor{Context: ctx, allowKeys: true}
+	return v.VisitFilterExpression(cond)
+}
+
 type Empty struct{}
 
 type Acc struct {
@@ -434,6 +732,7 @@ type Acc struct {
 
 type visitor struct {
 	*Context
+	allowKeys bool
 }
 
 // VisitFilterExpression visits all nodes in the filter expression tree to build a filter expression.
@@ -468,13 +767,15 @@ func (v *visitor) VisitFilterExpression(n interface{}) (string, error) {
 		return strings.Join(filter, " AND "), nil
 	case *parser.Condition:
 		switch {
+		case node.TupleIn != nil:
+			return "", errTupleIn
 		case node.Operand != nil:
-			if v.Context.IsKey(node.Operand.Operand.String()) {
+			if !v.allowKeys && v.Context.IsKey(node.Operand.Operand.String()) {
 				return "", fmt.Errorf("partition key %q may not appear in nested expression", node.Operand.Operand.String())
 			}
 			return v.VisitSimpleExpression(node.Operand), nil
 		case node.Function != nil:
-			if node.Function.FirstArgIsRef() && v.Context.IsKey(node.Function.Args[0].DocumentPath.String()) {
+			if !v.allowKeys && node.Function.FirstArgIsRef() && v.Context.IsKey(node.Function.Args[0].DocumentPath.String()) {
 				return "", fmt.Errorf("partition key %q may not appear in nested expression", node.Function.Args[0].DocumentPath)
 			}
 			return v.VisitSimpleExpression(node.Function), nil
@@ -530,7 +831,11 @@ func (v *visitor) VisitSimpleExpression(n interface{}) string {
 		return fmt.Sprintf("BETWEEN %s AND %s",
 			v.VisitSimpleExpression(node.Start), v.VisitSimpleExpression(node.End))
 	case *parser.In:
-		return fmt.Sprintf(" IN (%s)", v.VisitSimpleExpression(node.Values))
+		values := make([]string, len(node.Values))
+		for i, value := range node.Values {
+			values[i] = v.VisitSimpleExpression(value)
+		}
+		return fmt.Sprintf("IN (%s)", strings.Join(values, ", "))
 	case *parser.Operand:
 		if node.SymbolRef != nil {
 			return v.BuildPath(node.SymbolRef)
@@ -546,6 +851,18 @@ func (v *visitor) VisitSimpleExpression(n interface{}) string {
 	}
 }
 
+// buildOptionalProjectionExpression builds a ProjectionExpression, or returns nil if all attributes are selected.
+func buildOptionalProjectionExpression(ctx *Context, expr *parser.ProjectionExpression) (*string, error) {
+	if expr.All {
+		return nil, nil
+	}
+	projection, err := buildProjectionExpression(ctx, expr)
+	if err != nil {
+		return nil, err
+	}
+	return &projection, nil
+}
+
 func buildProjectionExpression(ctx *Context, expr *parser.ProjectionExpression) (string, error) {
 	cols := make([]*parser.DocumentPath, 0, len(expr.Columns))
 	for _, col := range expr.Columns {
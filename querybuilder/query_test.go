@@ -2,12 +2,15 @@ package querybuilder
 
 import (
 	"bufio"
+	"database/sql/driver"
 	"fmt"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/alecthomas/repr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/sebdah/goldie/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -107,6 +110,77 @@ func TestInvalidQueries(t *testing.T) {
 	g.Assert(t, "queries_invalid", []byte(testutil.MarshalJSON(parsed)))
 }
 
+func TestNewRequestsFanOut(t *testing.T) {
+	movies := schema.NewTableFromCreate(fixtures.Movies.Create)
+	prepareQuery := func(query string) *PreparedQuery {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		prepared, err := prepare(movies, ast.Select)
+		require.NoError(t, err)
+		return prepared
+	}
+
+	t.Run("query per distinct value", func(t *testing.T) {
+		prepared := prepareQuery(`SELECT * FROM movies WHERE title IN (?, ?, ?) AND year > ? LIMIT 5`)
+		reqs, err := prepared.NewRequests([]driver.NamedValue{
+			{Ordinal: 1, Value: "Big"},
+			{Ordinal: 2, Value: "Heat"},
+			{Ordinal: 3, Value: "Big"},
+			{Ordinal: 4, Value: int64(1980)},
+		})
+		require.NoError(t, err)
+		newReq := func(title string) *dynamodb.QueryInput {
+			return &dynamodb.QueryInput{
+				TableName:              aws.String("movies"),
+				KeyConditionExpression: aws.String("title = :_gen1 AND #year > :_pos4"),
+				ExpressionAttributeNames: map[string]*string{
+					"#year": aws.String("year"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":_gen1": {S: aws.String(title)},
+					":_pos4": {N: aws.String("1980")},
+				},
+				Limit: aws.Int64(5),
+			}
+		}
+		require.Equal(t, []*dynamodb.QueryInput{newReq("Big"), newReq("Heat")}, reqs)
+		require.Equal(t, 5, prepared.Limit)
+	})
+
+	t.Run("query per OR condition", func(t *testing.T) {
+		prepared := prepareQuery(`SELECT * FROM movies WHERE title = :a OR title = :b AND info.rating > 8`)
+		reqs, err := prepared.NewRequests([]driver.NamedValue{
+			{Name: "a", Value: "Big"},
+			{Name: "b", Value: "Heat"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*dynamodb.QueryInput{
+			{
+				TableName:              aws.String("movies"),
+				KeyConditionExpression: aws.String("title = :a"),
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":a": {S: aws.String("Big")},
+				},
+			},
+			{
+				TableName:              aws.String("movies"),
+				KeyConditionExpression: aws.String("title = :b"),
+				FilterExpression:       aws.String("info.rating > :_gen1"),
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":b":     {S: aws.String("Heat")},
+					":_gen1": {N: aws.String("8")},
+				},
+			},
+		}, reqs)
+
+		_, err = prepared.NewRequests([]driver.NamedValue{
+			{Name: "a", Value: "Big"},
+			{Name: "b", Value: "Big"},
+		})
+		require.EqualError(t, err, "each partition key value may only be matched by one OR condition")
+	})
+}
+
 func TestSubstitute(t *testing.T) {
 	ctx := NewContext(&schema.Table{}, "")
 	require.Equal(t, "hello", ctx.substitute("hello"))
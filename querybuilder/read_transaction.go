@@ -0,0 +1,157 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// maxTransactGetItems is the maximum number of items DynamoDB allows in a TransactGetItems request.
+const maxTransactGetItems = 100
+
+var errNotPointRead = errors.New("only SELECTs that match items by their full primary key, such as WHERE hash = :hash AND sort = :sort, may be used in a read-only transaction")
+
+// ReadTransaction collects the point reads made in a read-only transaction, and reads them together with
+// TransactGetItems so that they are consistent with each other. Reads are collected until the results of any of
+// them are needed, at which point all pending reads are made.
+type ReadTransaction struct {
+	mu      sync.Mutex
+	pending *readBatch
+}
+
+// readBatch is a set of reads made in a single TransactGetItems request.
+type readBatch struct {
+	gets []*dynamodb.TransactGetItem
+	// Maps item ids to their index in gets.
+	ids   map[string]int
+	done  bool
+	items []map[string]*dynamodb.AttributeValue
+	err   error
+}
+
+func NewReadTransaction() *ReadTransaction {
+	return &ReadTransaction{}
+}
+
+// ReadItems reads the items of a read.
+type ReadItems func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) ([]map[string]*dynamodb.AttributeValue, error)
+
+// Read adds the items matched by a point SELECT to the pending reads. The returned function makes all pending reads
+// if they have not been made yet, and returns the items that exist.
+func (t *ReadTransaction) Read(pq *PreparedQuery, args []driver.NamedValue) (ReadItems, error) {
+	gets, err := pq.pointReads(args)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending == nil {
+		t.pending = &readBatch{ids: make(map[string]int)}
+	}
+	batch := t.pending
+	// Validate before adding any of the reads, so that a failed Read does not leave partial reads behind.
+	var added int
+	for _, get := range gets {
+		id := fmt.Sprintf("%q:%s", *get.TableName, keyString(get.Key))
+		if i, ok := batch.ids[id]; ok {
+			if aws.StringValue(batch.gets[i].Get.ProjectionExpression) != aws.StringValue(get.ProjectionExpression) {
+				return nil, fmt.Errorf("item %s in table %q may only be read with one set of columns in a transaction", keyString(get.Key), *get.TableName)
+			}
+			continue
+		}
+		added++
+	}
+	if len(batch.gets)+added > maxTransactGetItems {
+		return nil, fmt.Errorf("a read-only transaction may read at most %d items at a time", maxTransactGetItems)
+	}
+	indexes := make([]int, len(gets))
+	for i, get := range gets {
+		id := fmt.Sprintf("%q:%s", *get.TableName, keyString(get.Key))
+		idx, ok := batch.ids[id]
+		if !ok {
+			idx = len(batch.gets)
+			batch.ids[id] = idx
+			batch.gets = append(batch.gets, &dynamodb.TransactGetItem{Get: get})
+		}
+		indexes[i] = idx
+	}
+	return func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) ([]map[string]*dynamodb.AttributeValue, error) {
+		if err := t.read(ctx, dynamo, batch); err != nil {
+			return nil, err
+		}
+		items := make([]map[string]*dynamodb.AttributeValue, 0, len(indexes))
+		for _, idx := range indexes {
+			if item := batch.items[idx]; item != nil {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	}, nil
+}
+
+// read makes the reads in the batch, if they have not been made yet.
+func (t *ReadTransaction) read(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, batch *readBatch) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if batch.done {
+		return batch.err
+	}
+	batch.done = true
+	if t.pending == batch {
+		t.pending = nil
+	}
+	resp, err := dynamo.TransactGetItemsWithContext(ctx, &dynamodb.TransactGetItemsInput{TransactItems: batch.gets})
+	if err != nil {
+		batch.err = err
+		return err
+	}
+	batch.items = make([]map[string]*dynamodb.AttributeValue, len(batch.gets))
+	for i, item := range resp.Responses {
+		batch.items[i] = item.Item
+	}
+	return nil
+}
+
+// pointReads returns a Get for each item matched by a SELECT that matches items by their full primary key.
+func (pq *PreparedQuery) pointReads(args []driver.NamedValue) ([]*dynamodb.Get, error) {
+	switch {
+	case pq.GetItem != nil:
+		req, err := pq.NewGetItemRequest(args)
+		if err != nil {
+			return nil, err
+		}
+		return []*dynamodb.Get{{
+			TableName:                req.TableName,
+			Key:                      req.Key,
+			ProjectionExpression:     req.ProjectionExpression,
+			ExpressionAttributeNames: req.ExpressionAttributeNames,
+		}}, nil
+	case pq.BatchGet != nil:
+		reqs, err := pq.NewBatchGetRequests(args)
+		if err != nil {
+			return nil, err
+		}
+		var gets []*dynamodb.Get
+		for _, req := range reqs {
+			for table, keys := range req.RequestItems {
+				for _, key := range keys.Keys {
+					gets = append(gets, &dynamodb.Get{
+						TableName:                aws.String(table),
+						Key:                      key,
+						ProjectionExpression:     keys.ProjectionExpression,
+						ExpressionAttributeNames: keys.ExpressionAttributeNames,
+					})
+				}
+			}
+		}
+		return gets, nil
+	default:
+		return nil, errNotPointRead
+	}
+}
@@ -0,0 +1,97 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+// fakeTransactGet returns the key of each requested item as the item, except for items with title "missing".
+type fakeTransactGet struct {
+	dynamodbiface.DynamoDBAPI
+	reqs []*dynamodb.TransactGetItemsInput
+}
+
+func (d *fakeTransactGet) TransactGetItemsWithContext(ctx aws.Context, req *dynamodb.TransactGetItemsInput, opts ...request.Option) (*dynamodb.TransactGetItemsOutput, error) {
+	d.reqs = append(d.reqs, req)
+	resp := &dynamodb.TransactGetItemsOutput{}
+	for _, get := range req.TransactItems {
+		item := &dynamodb.ItemResponse{}
+		if *get.Get.Key["title"].S != "missing" {
+			item.Item = get.Get.Key
+		}
+		resp.Responses = append(resp.Responses, item)
+	}
+	return resp, nil
+}
+
+func TestReadTransaction(t *testing.T) {
+	movies := schema.NewTableFromCreate(fixtures.Movies.Create)
+	prepareQuery := func(query string) *PreparedQuery {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		prepared, err := prepare(movies, ast.Select)
+		require.NoError(t, err)
+		return prepared
+	}
+	get := prepareQuery(`SELECT * FROM movies WHERE title = ? AND year = ?`)
+	batchGet := prepareQuery(`SELECT * FROM movies WHERE (title, year) IN ((?, ?), (?, ?))`)
+	key := func(title string, year string) map[string]*dynamodb.AttributeValue {
+		return map[string]*dynamodb.AttributeValue{"title": {S: aws.String(title)}, "year": {N: aws.String(year)}}
+	}
+	ctx := context.Background()
+
+	t.Run("reads are made together", func(t *testing.T) {
+		tx := NewReadTransaction()
+		dynamo := &fakeTransactGet{}
+		readBig, err := tx.Read(get, []driver.NamedValue{{Ordinal: 1, Value: "Big"}, {Ordinal: 2, Value: int64(1988)}})
+		require.NoError(t, err)
+		readBatch, err := tx.Read(batchGet, []driver.NamedValue{
+			{Ordinal: 1, Value: "missing"}, {Ordinal: 2, Value: int64(2000)},
+			{Ordinal: 3, Value: "Big"}, {Ordinal: 4, Value: int64(1988)},
+		})
+		require.NoError(t, err)
+
+		items, err := readBatch(ctx, dynamo)
+		require.NoError(t, err)
+		require.Equal(t, []map[string]*dynamodb.AttributeValue{key("Big", "1988")}, items)
+		items, err = readBig(ctx, dynamo)
+		require.NoError(t, err)
+		require.Equal(t, []map[string]*dynamodb.AttributeValue{key("Big", "1988")}, items)
+		require.Len(t, dynamo.reqs, 1)
+		require.Len(t, dynamo.reqs[0].TransactItems, 2)
+
+		// Reads after the first flush are made in a new request
+		readHeat, err := tx.Read(get, []driver.NamedValue{{Ordinal: 1, Value: "Heat"}, {Ordinal: 2, Value: int64(1995)}})
+		require.NoError(t, err)
+		items, err = readHeat(ctx, dynamo)
+		require.NoError(t, err)
+		require.Equal(t, []map[string]*dynamodb.AttributeValue{key("Heat", "1995")}, items)
+		require.Len(t, dynamo.reqs, 2)
+	})
+
+	t.Run("only point reads", func(t *testing.T) {
+		tx := NewReadTransaction()
+		_, err := tx.Read(prepareQuery(`SELECT * FROM movies WHERE title = ?`), []driver.NamedValue{{Ordinal: 1, Value: "Big"}})
+		require.Equal(t, errNotPointRead, err)
+	})
+
+	t.Run("same item with different columns", func(t *testing.T) {
+		tx := NewReadTransaction()
+		args := []driver.NamedValue{{Ordinal: 1, Value: "Big"}, {Ordinal: 2, Value: int64(1988)}}
+		_, err := tx.Read(get, args)
+		require.NoError(t, err)
+		_, err = tx.Read(prepareQuery(`SELECT title FROM movies WHERE title = ? AND year = ?`), args)
+		require.EqualError(t, err, `item title="Big", year=1988 in table "movies" may only be read with one set of columns in a transaction`)
+	})
+}
@@ -0,0 +1,246 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// ShowStmt is implemented by statements that describe the database instead of reading items.
+type ShowStmt interface {
+	Show(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) (*ShowResult, error)
+}
+
+type showStatementFunc func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) (*ShowResult, error)
+
+func (s showStatementFunc) Show(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) (*ShowResult, error) {
+	return s(ctx, dynamo)
+}
+
+// ShowResult is the result of a ShowStmt, as rows of columns.
+type ShowResult struct {
+	Columns []string
+	Rows    [][]driver.Value
+}
+
+// PrepareShowTables lists the tables with ListTables. Each row is a table name.
+func PrepareShowTables(show *parser.ShowTables) (ShowStmt, error) {
+	match := func(string) bool { return true }
+	var prefix string
+	if show.Like != nil {
+		re, err := likeRegexp(*show.Like)
+		if err != nil {
+			return nil, err
+		}
+		match = re.MatchString
+		prefix = likePrefix(*show.Like)
+	}
+	return showStatementFunc(func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) (*ShowResult, error) {
+		result := &ShowResult{Columns: []string{"name"}}
+		req := &dynamodb.ListTablesInput{}
+		for {
+			resp, err := dynamo.ListTablesWithContext(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range resp.TableNames {
+				// Tables are listed in order, so no more tables can match once past the prefix.
+				if *name > prefix && !strings.HasPrefix(*name, prefix) {
+					return result, nil
+				}
+				if match(*name) {
+					result.Rows = append(result.Rows, []driver.Value{*name})
+				}
+			}
+			if resp.LastEvaluatedTableName == nil {
+				return result, nil
+			}
+			req.ExclusiveStartTableName = resp.LastEvaluatedTableName
+		}
+	}), nil
+}
+
+// PrepareDescribe describes a table in a single row.
+func PrepareDescribe(describe *parser.Describe) (ShowStmt, error) {
+	return showStatementFunc(func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) (*ShowResult, error) {
+		desc, err := dynamo.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{TableName: &describe.Table})
+		if err != nil {
+			return nil, err
+		}
+		ttl, err := dynamo.DescribeTimeToLiveWithContext(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: &describe.Table})
+		if err != nil {
+			return nil, err
+		}
+		return describeTable(desc.Table, ttl.TimeToLiveDescription), nil
+	}), nil
+}
+
+func describeTable(table *dynamodb.TableDescription, ttl *dynamodb.TimeToLiveDescription) *ShowResult {
+	hash, sort := keySchemaValues(table.KeySchema)
+	attrs := make([]string, 0, len(table.AttributeDefinitions))
+	for _, attr := range table.AttributeDefinitions {
+		attrs = append(attrs, *attr.AttributeName+" "+*attr.AttributeType)
+	}
+	billingMode := dynamodb.BillingModeProvisioned
+	if table.BillingModeSummary != nil && table.BillingModeSummary.BillingMode != nil {
+		billingMode = *table.BillingModeSummary.BillingMode
+	}
+	var readCapacity, writeCapacity driver.Value
+	if billingMode == dynamodb.BillingModeProvisioned && table.ProvisionedThroughput != nil {
+		readCapacity = optionalInt64(table.ProvisionedThroughput.ReadCapacityUnits)
+		writeCapacity = optionalInt64(table.ProvisionedThroughput.WriteCapacityUnits)
+	}
+	var stream driver.Value
+	if spec := table.StreamSpecification; spec != nil && aws.BoolValue(spec.StreamEnabled) {
+		stream = optionalStringValue(spec.StreamViewType)
+	}
+	var ttlAttribute, ttlStatus driver.Value
+	if ttl != nil {
+		ttlAttribute = optionalStringValue(ttl.AttributeName)
+		ttlStatus = optionalStringValue(ttl.TimeToLiveStatus)
+	}
+	return &ShowResult{
+		Columns: []string{
+			"name", "status", "hash_key", "range_key", "attributes", "billing_mode", "read_capacity", "write_capacity",
+			"item_count", "size_bytes", "stream", "ttl_attribute", "ttl_status",
+		},
+		Rows: [][]driver.Value{{
+			*table.TableName,
+			optionalStringValue(table.TableStatus),
+			hash,
+			sort,
+			strings.Join(attrs, ", "),
+			billingMode,
+			readCapacity,
+			writeCapacity,
+			optionalInt64(table.ItemCount),
+			optionalInt64(table.TableSizeBytes),
+			stream,
+			ttlAttribute,
+			ttlStatus,
+		}},
+	}
+}
+
+// PrepareShowIndexes lists the secondary indexes of a table, with a row per index.
+func PrepareShowIndexes(show *parser.ShowIndexes) (ShowStmt, error) {
+	return showStatementFunc(func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) (*ShowResult, error) {
+		desc, err := dynamo.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{TableName: &show.Table})
+		if err != nil {
+			return nil, err
+		}
+		return showIndexes(desc.Table), nil
+	}), nil
+}
+
+func showIndexes(table *dynamodb.TableDescription) *ShowResult {
+	result := &ShowResult{
+		Columns: []string{"name", "type", "hash_key", "range_key", "projection", "status", "backfilling"},
+	}
+	for _, index := range table.GlobalSecondaryIndexes {
+		hash, sort := keySchemaValues(index.KeySchema)
+		result.Rows = append(result.Rows, []driver.Value{
+			*index.IndexName,
+			"GLOBAL",
+			hash,
+			sort,
+			projectionValue(index.Projection),
+			optionalStringValue(index.IndexStatus),
+			aws.BoolValue(index.Backfilling),
+		})
+	}
+	// Local indexes are created with the table, and have no status of their own.
+	for _, index := range table.LocalSecondaryIndexes {
+		hash, sort := keySchemaValues(index.KeySchema)
+		result.Rows = append(result.Rows, []driver.Value{
+			*index.IndexName,
+			"LOCAL",
+			hash,
+			sort,
+			projectionValue(index.Projection),
+			nil,
+			nil,
+		})
+	}
+	return result
+}
+
+// keySchemaValues returns the names of the hash and sort keys, with a nil sort key if there is none.
+func keySchemaValues(schema []*dynamodb.KeySchemaElement) (hash, sort driver.Value) {
+	hashKey, sortKey := keySchema(schema)
+	if sortKey == "" {
+		return hashKey, nil
+	}
+	return hashKey, sortKey
+}
+
+func keySchema(schema []*dynamodb.KeySchemaElement) (hash, sort string) {
+	for _, key := range schema {
+		switch *key.KeyType {
+		case dynamodb.KeyTypeHash:
+			hash = *key.AttributeName
+		case dynamodb.KeyTypeRange:
+			sort = *key.AttributeName
+		}
+	}
+	return
+}
+
+// projectionValue formats a projection as it is written in CREATE TABLE, such as INCLUDE (a, b).
+func projectionValue(projection *dynamodb.Projection) driver.Value {
+	if projection == nil || projection.ProjectionType == nil {
+		return nil
+	}
+	if *projection.ProjectionType != dynamodb.ProjectionTypeInclude {
+		return *projection.ProjectionType
+	}
+	return fmt.Sprintf("INCLUDE (%s)", strings.Join(aws.StringValueSlice(projection.NonKeyAttributes), ", "))
+}
+
+func optionalStringValue(s *string) driver.Value {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func optionalInt64(i *int64) driver.Value {
+	if i == nil {
+		return nil
+	}
+	return *i
+}
+
+// likeRegexp compiles a LIKE pattern, where % matches any number of characters and _ matches a single character.
+func likeRegexp(pattern string) (*regexp.Regexp, error) {
+	var buf strings.Builder
+	buf.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			buf.WriteString(".*")
+		case '_':
+			buf.WriteString(".")
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	buf.WriteString("$")
+	return regexp.Compile(buf.String())
+}
+
+// likePrefix returns the literal prefix of a LIKE pattern, before its first wildcard.
+func likePrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, "%_"); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
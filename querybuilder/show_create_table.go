@@ -0,0 +1,225 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// PrepareShowCreateTable returns the CREATE TABLE statement that creates a table like an existing one, in a single
+// row.
+func PrepareShowCreateTable(show *parser.ShowCreateTable) (ShowStmt, error) {
+	return showStatementFunc(func(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) (*ShowResult, error) {
+		desc, err := dynamo.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{TableName: &show.Table})
+		if err != nil {
+			return nil, err
+		}
+		ttl, err := dynamo.DescribeTimeToLiveWithContext(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: &show.Table})
+		if err != nil {
+			return nil, err
+		}
+		tags, err := listTags(ctx, dynamo, desc.Table.TableArn)
+		if err != nil {
+			return nil, err
+		}
+		stmt, err := createTableStatement(desc.Table, ttl.TimeToLiveDescription, tags)
+		if err != nil {
+			return nil, err
+		}
+		return &ShowResult{
+			Columns: []string{"name", "statement"},
+			Rows:    [][]driver.Value{{*desc.Table.TableName, stmt.String()}},
+		}, nil
+	}), nil
+}
+
+func listTags(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, arn *string) ([]*dynamodb.Tag, error) {
+	if arn == nil {
+		return nil, nil
+	}
+	var tags []*dynamodb.Tag
+	req := &dynamodb.ListTagsOfResourceInput{ResourceArn: arn}
+	for {
+		resp, err := dynamo.ListTagsOfResourceWithContext(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, resp.Tags...)
+		if resp.NextToken == nil {
+			return tags, nil
+		}
+		req.NextToken = resp.NextToken
+	}
+}
+
+// createTableStatement builds the CREATE TABLE statement for a table description. It is the reverse of
+// buildCreateTable, so the statement creates an equivalent table.
+func createTableStatement(table *dynamodb.TableDescription, ttl *dynamodb.TimeToLiveDescription, tags []*dynamodb.Tag) (*parser.CreateTable, error) {
+	stmt := &parser.CreateTable{Table: *table.TableName}
+	payPerRequest := false
+	if table.BillingModeSummary != nil && table.BillingModeSummary.BillingMode != nil {
+		stmt.BillingMode = table.BillingModeSummary.BillingMode
+		payPerRequest = *stmt.BillingMode == dynamodb.BillingModePayPerRequest
+	}
+	if !payPerRequest {
+		stmt.ProvisionedThroughput = describedThroughput(table.ProvisionedThroughput)
+	}
+
+	hash, sort := keySchema(table.KeySchema)
+	hashIndex, sortIndex := -1, -1
+	for _, def := range table.AttributeDefinitions {
+		attr := &parser.TableAttr{Name: *def.AttributeName}
+		switch *def.AttributeType {
+		case dynamodb.ScalarAttributeTypeS:
+			attr.Type = "STRING"
+		case dynamodb.ScalarAttributeTypeN:
+			attr.Type = "NUMBER"
+		case dynamodb.ScalarAttributeTypeB:
+			attr.Type = "BINARY"
+		default:
+			return nil, fmt.Errorf("attribute %q has unknown type %s", attr.Name, *def.AttributeType)
+		}
+		switch attr.Name {
+		case hash:
+			attr.Key = dynamodb.KeyTypeHash
+			hashIndex = len(stmt.Entries)
+		case sort:
+			attr.Key = dynamodb.KeyTypeRange
+			sortIndex = len(stmt.Entries)
+		}
+		stmt.Entries = append(stmt.Entries, &parser.CreateTableEntry{Attr: attr})
+	}
+	// The key schema is built in the order of the attributes, and the hash key must come first.
+	if sortIndex >= 0 && sortIndex < hashIndex {
+		stmt.Entries[hashIndex], stmt.Entries[sortIndex] = stmt.Entries[sortIndex], stmt.Entries[hashIndex]
+	}
+
+	for _, index := range table.GlobalSecondaryIndexes {
+		hash, sort := keySchema(index.KeySchema)
+		gsi := &parser.GlobalSecondaryIndex{
+			Name:         *index.IndexName,
+			PartitionKey: hash,
+			SortKey:      sort,
+			Projection:   describedProjection(index.Projection),
+		}
+		if !payPerRequest {
+			gsi.ProvisionedThroughput = describedThroughput(index.ProvisionedThroughput)
+		}
+		stmt.Entries = append(stmt.Entries, &parser.CreateTableEntry{GlobalSecondaryIndex: gsi})
+	}
+	for _, index := range table.LocalSecondaryIndexes {
+		_, sort := keySchema(index.KeySchema)
+		stmt.Entries = append(stmt.Entries, &parser.CreateTableEntry{LocalSecondaryIndex: &parser.LocalSecondaryIndex{
+			Name:       *index.IndexName,
+			SortKey:    sort,
+			Projection: describedProjection(index.Projection),
+		}})
+	}
+	if err := checkIdents(stmt); err != nil {
+		return nil, err
+	}
+	stmt.Options = describedTableOptions(table, ttl, tags)
+	return stmt, nil
+}
+
+func describedThroughput(throughput *dynamodb.ProvisionedThroughputDescription) *parser.ProvisionedThroughput {
+	if throughput == nil {
+		return nil
+	}
+	return &parser.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64Value(throughput.ReadCapacityUnits),
+		WriteCapacityUnits: aws.Int64Value(throughput.WriteCapacityUnits),
+	}
+}
+
+func describedProjection(projection *dynamodb.Projection) *parser.Projection {
+	switch aws.StringValue(projection.ProjectionType) {
+	case dynamodb.ProjectionTypeKeysOnly:
+		return &parser.Projection{KeysOnly: true}
+	case dynamodb.ProjectionTypeInclude:
+		return &parser.Projection{Include: aws.StringValueSlice(projection.NonKeyAttributes)}
+	default:
+		return &parser.Projection{All: true}
+	}
+}
+
+// describedTableOptions returns the WITH options of the table, in the order they are documented.
+func describedTableOptions(table *dynamodb.TableDescription, ttl *dynamodb.TimeToLiveDescription, tags []*dynamodb.Tag) []*parser.TableOption {
+	var options []*parser.TableOption
+	ident := func(name, value string) {
+		options = append(options, &parser.TableOption{Name: name, Ident: aws.String(value)})
+	}
+	str := func(name, value string) {
+		options = append(options, &parser.TableOption{Name: name, Value: &parser.JSONValue{Scalar: parser.Scalar{Str: aws.String(value)}}})
+	}
+
+	if spec := table.StreamSpecification; spec != nil && aws.BoolValue(spec.StreamEnabled) {
+		ident("STREAM", *spec.StreamViewType)
+	}
+	if ttl != nil && ttl.AttributeName != nil {
+		switch aws.StringValue(ttl.TimeToLiveStatus) {
+		case dynamodb.TimeToLiveStatusEnabled, dynamodb.TimeToLiveStatusEnabling:
+			str("TTL", *ttl.AttributeName)
+		}
+	}
+	// Tables encrypted with a key owned by DynamoDB have no SSE description.
+	if sse := table.SSEDescription; sse != nil && aws.StringValue(sse.SSEType) == dynamodb.SSETypeKms {
+		switch aws.StringValue(sse.Status) {
+		case dynamodb.SSEStatusEnabled, dynamodb.SSEStatusEnabling, dynamodb.SSEStatusUpdating:
+			ident("SSE", dynamodb.SSETypeKms)
+			if sse.KMSMasterKeyArn != nil {
+				str("SSE_KMS_KEY", *sse.KMSMasterKeyArn)
+			}
+		}
+	}
+	if table.TableClassSummary != nil && table.TableClassSummary.TableClass != nil {
+		ident("TABLE_CLASS", *table.TableClassSummary.TableClass)
+	}
+	if aws.BoolValue(table.DeletionProtectionEnabled) {
+		enabled := parser.Boolean(true)
+		options = append(options, &parser.TableOption{Name: "DELETION_PROTECTION", Value: &parser.JSONValue{Scalar: parser.Scalar{Boolean: &enabled}}})
+	}
+	object := &parser.JSONObject{}
+	for _, tag := range tags {
+		// Tags with the aws: prefix are added by AWS, and cannot be set by users.
+		if strings.HasPrefix(*tag.Key, "aws:") {
+			continue
+		}
+		object.Entries = append(object.Entries, &parser.JSONObjectEntry{
+			Key:   *tag.Key,
+			Value: &parser.JSONValue{Scalar: parser.Scalar{Str: tag.Value}},
+		})
+	}
+	if len(object.Entries) > 0 {
+		options = append(options, &parser.TableOption{Name: "TAGS", Value: &parser.JSONValue{Object: object}})
+	}
+	return options
+}
+
+// checkIdents checks that the names of the attributes and indexes can be quoted with backticks.
+func checkIdents(stmt *parser.CreateTable) error {
+	var names []string
+	for _, entry := range stmt.Entries {
+		switch {
+		case entry.Attr != nil:
+			names = append(names, entry.Attr.Name)
+		case entry.GlobalSecondaryIndex != nil:
+			names = append(names, entry.GlobalSecondaryIndex.Projection.Include...)
+		case entry.LocalSecondaryIndex != nil:
+			names = append(names, entry.LocalSecondaryIndex.Projection.Include...)
+		}
+	}
+	for _, name := range names {
+		if strings.Contains(name, "`") {
+			return fmt.Errorf("attribute %q cannot be written in CREATE TABLE, since it contains a backtick", name)
+		}
+	}
+	return nil
+}
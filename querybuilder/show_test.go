@@ -0,0 +1,280 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// fakeDescribe lists its tables two per page, and describes a table with a stream, time to live and indexes.
+type fakeDescribe struct {
+	dynamodbiface.DynamoDBAPI
+	tables []string
+	lists  int
+}
+
+func (d *fakeDescribe) ListTablesWithContext(ctx aws.Context, req *dynamodb.ListTablesInput, opts ...request.Option) (*dynamodb.ListTablesOutput, error) {
+	d.lists++
+	start := 0
+	if req.ExclusiveStartTableName != nil {
+		for i, name := range d.tables {
+			if name == *req.ExclusiveStartTableName {
+				start = i + 1
+			}
+		}
+	}
+	resp := &dynamodb.ListTablesOutput{}
+	for _, name := range d.tables[start:] {
+		if len(resp.TableNames) == 2 {
+			resp.LastEvaluatedTableName = resp.TableNames[1]
+			break
+		}
+		resp.TableNames = append(resp.TableNames, aws.String(name))
+	}
+	return resp, nil
+}
+
+func (d *fakeDescribe) DescribeTableWithContext(ctx aws.Context, req *dynamodb.DescribeTableInput, opts ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	return &dynamodb.DescribeTableOutput{Table: &dynamodb.TableDescription{
+		TableName:   req.TableName,
+		TableStatus: aws.String("ACTIVE"),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("UserId"), KeyType: aws.String("HASH")},
+			{AttributeName: aws.String("GameTitle"), KeyType: aws.String("RANGE")},
+		},
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("UserId"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("GameTitle"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("TopScore"), AttributeType: aws.String("N")},
+		},
+		BillingModeSummary: &dynamodb.BillingModeSummary{BillingMode: aws.String("PAY_PER_REQUEST")},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughputDescription{
+			ReadCapacityUnits:  aws.Int64(0),
+			WriteCapacityUnits: aws.Int64(0),
+		},
+		ItemCount:      aws.Int64(3),
+		TableSizeBytes: aws.Int64(120),
+		StreamSpecification: &dynamodb.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: aws.String("NEW_IMAGE"),
+		},
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndexDescription{{
+			IndexName: aws.String("GameTitleIndex"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("GameTitle"), KeyType: aws.String("HASH")},
+				{AttributeName: aws.String("TopScore"), KeyType: aws.String("RANGE")},
+			},
+			Projection: &dynamodb.Projection{
+				ProjectionType:   aws.String("INCLUDE"),
+				NonKeyAttributes: aws.StringSlice([]string{"Wins", "Losses"}),
+			},
+			IndexStatus: aws.String("CREATING"),
+			Backfilling: aws.Bool(true),
+		}},
+		LocalSecondaryIndexes: []*dynamodb.LocalSecondaryIndexDescription{{
+			IndexName: aws.String("TopScoreIndex"),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String("UserId"), KeyType: aws.String("HASH")},
+				{AttributeName: aws.String("TopScore"), KeyType: aws.String("RANGE")},
+			},
+			Projection: &dynamodb.Projection{ProjectionType: aws.String("KEYS_ONLY")},
+		}},
+	}}, nil
+}
+
+func (d *fakeDescribe) DescribeTimeToLiveWithContext(ctx aws.Context, req *dynamodb.DescribeTimeToLiveInput, opts ...request.Option) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	return &dynamodb.DescribeTimeToLiveOutput{TimeToLiveDescription: &dynamodb.TimeToLiveDescription{
+		AttributeName:    aws.String("ExpiresAt"),
+		TimeToLiveStatus: aws.String("ENABLED"),
+	}}, nil
+}
+
+func TestShow(t *testing.T) {
+	show := func(t *testing.T, dynamo dynamodbiface.DynamoDBAPI, query string) *ShowResult {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		var stmt ShowStmt
+		switch {
+		case ast.ShowTables != nil:
+			stmt, err = PrepareShowTables(ast.ShowTables)
+		case ast.ShowIndexes != nil:
+			stmt, err = PrepareShowIndexes(ast.ShowIndexes)
+		default:
+			stmt, err = PrepareDescribe(ast.Describe)
+		}
+		require.NoError(t, err)
+		result, err := stmt.Show(context.Background(), dynamo)
+		require.NoError(t, err)
+		return result
+	}
+	tables := []string{"gamescores", "movies", "movies_2020", "movies_archive", "users"}
+
+	t.Run("show tables", func(t *testing.T) {
+		dynamo := &fakeDescribe{tables: tables}
+		result := show(t, dynamo, `SHOW TABLES`)
+		require.Equal(t, []string{"name"}, result.Columns)
+		require.Equal(t, [][]driver.Value{{"gamescores"}, {"movies"}, {"movies_2020"}, {"movies_archive"}, {"users"}}, result.Rows)
+		require.Equal(t, 3, dynamo.lists)
+	})
+
+	t.Run("show tables like", func(t *testing.T) {
+		dynamo := &fakeDescribe{tables: tables}
+		result := show(t, dynamo, `SHOW TABLES LIKE 'movies_%'`)
+		require.Equal(t, [][]driver.Value{{"movies_2020"}, {"movies_archive"}}, result.Rows)
+		require.Equal(t, 3, dynamo.lists)
+
+		dynamo = &fakeDescribe{tables: tables}
+		result = show(t, dynamo, `SHOW TABLES LIKE 'm%'`)
+		require.Equal(t, [][]driver.Value{{"movies"}, {"movies_2020"}, {"movies_archive"}}, result.Rows)
+
+		dynamo = &fakeDescribe{tables: tables}
+		result = show(t, dynamo, `SHOW TABLES LIKE 'gamescore_'`)
+		require.Equal(t, [][]driver.Value{{"gamescores"}}, result.Rows)
+		require.Equal(t, 1, dynamo.lists, "stops listing once past the prefix")
+	})
+
+	t.Run("describe", func(t *testing.T) {
+		result := show(t, &fakeDescribe{}, `DESCRIBE gamescores`)
+		require.Equal(t, []string{
+			"name", "status", "hash_key", "range_key", "attributes", "billing_mode", "read_capacity", "write_capacity",
+			"item_count", "size_bytes", "stream", "ttl_attribute", "ttl_status",
+		}, result.Columns)
+		require.Equal(t, [][]driver.Value{{
+			"gamescores", "ACTIVE", "UserId", "GameTitle", "UserId S, GameTitle S, TopScore N", "PAY_PER_REQUEST", nil, nil,
+			int64(3), int64(120), "NEW_IMAGE", "ExpiresAt", "ENABLED",
+		}}, result.Rows)
+	})
+
+	t.Run("show indexes", func(t *testing.T) {
+		result := show(t, &fakeDescribe{}, `SHOW INDEXES FROM gamescores`)
+		require.Equal(t, []string{"name", "type", "hash_key", "range_key", "projection", "status", "backfilling"}, result.Columns)
+		require.Equal(t, [][]driver.Value{
+			{"GameTitleIndex", "GLOBAL", "GameTitle", "TopScore", "INCLUDE (Wins, Losses)", "CREATING", true},
+			{"TopScoreIndex", "LOCAL", "UserId", "TopScore", "KEYS_ONLY", nil, nil},
+		}, result.Rows)
+	})
+}
+
+// createdTable describes the table that a CreateTable request, its time to live and its tags would create.
+type createdTable struct {
+	dynamodbiface.DynamoDBAPI
+	req  *dynamodb.CreateTableInput
+	ttl  *dynamodb.UpdateTimeToLiveInput
+	tags []*dynamodb.Tag
+}
+
+func (d *createdTable) DescribeTableWithContext(ctx aws.Context, req *dynamodb.DescribeTableInput, opts ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	throughput := func(t *dynamodb.ProvisionedThroughput) *dynamodb.ProvisionedThroughputDescription {
+		if t == nil {
+			return &dynamodb.ProvisionedThroughputDescription{ReadCapacityUnits: aws.Int64(0), WriteCapacityUnits: aws.Int64(0)}
+		}
+		return &dynamodb.ProvisionedThroughputDescription{ReadCapacityUnits: t.ReadCapacityUnits, WriteCapacityUnits: t.WriteCapacityUnits}
+	}
+	create := d.req
+	table := &dynamodb.TableDescription{
+		TableName:                 create.TableName,
+		TableArn:                  aws.String("arn:aws:dynamodb:us-west-2:123456789012:table/" + *create.TableName),
+		AttributeDefinitions:      create.AttributeDefinitions,
+		KeySchema:                 create.KeySchema,
+		ProvisionedThroughput:     throughput(create.ProvisionedThroughput),
+		StreamSpecification:       create.StreamSpecification,
+		DeletionProtectionEnabled: create.DeletionProtectionEnabled,
+	}
+	if create.BillingMode != nil {
+		table.BillingModeSummary = &dynamodb.BillingModeSummary{BillingMode: create.BillingMode}
+	}
+	if create.TableClass != nil {
+		table.TableClassSummary = &dynamodb.TableClassSummary{TableClass: create.TableClass}
+	}
+	if sse := create.SSESpecification; sse != nil && aws.BoolValue(sse.Enabled) {
+		table.SSEDescription = &dynamodb.SSEDescription{Status: aws.String("ENABLED"), SSEType: sse.SSEType, KMSMasterKeyArn: sse.KMSMasterKeyId}
+	}
+	for _, gsi := range create.GlobalSecondaryIndexes {
+		table.GlobalSecondaryIndexes = append(table.GlobalSecondaryIndexes, &dynamodb.GlobalSecondaryIndexDescription{
+			IndexName:             gsi.IndexName,
+			KeySchema:             gsi.KeySchema,
+			Projection:            gsi.Projection,
+			ProvisionedThroughput: throughput(gsi.ProvisionedThroughput),
+		})
+	}
+	for _, lsi := range create.LocalSecondaryIndexes {
+		table.LocalSecondaryIndexes = append(table.LocalSecondaryIndexes, &dynamodb.LocalSecondaryIndexDescription{
+			IndexName:  lsi.IndexName,
+			KeySchema:  append([]*dynamodb.KeySchemaElement{create.KeySchema[0]}, lsi.KeySchema...),
+			Projection: lsi.Projection,
+		})
+	}
+	return &dynamodb.DescribeTableOutput{Table: table}, nil
+}
+
+func (d *createdTable) DescribeTimeToLiveWithContext(ctx aws.Context, req *dynamodb.DescribeTimeToLiveInput, opts ...request.Option) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	if d.ttl == nil {
+		return &dynamodb.DescribeTimeToLiveOutput{TimeToLiveDescription: &dynamodb.TimeToLiveDescription{TimeToLiveStatus: aws.String("DISABLED")}}, nil
+	}
+	return &dynamodb.DescribeTimeToLiveOutput{TimeToLiveDescription: &dynamodb.TimeToLiveDescription{
+		AttributeName:    d.ttl.TimeToLiveSpecification.AttributeName,
+		TimeToLiveStatus: aws.String("ENABLED"),
+	}}, nil
+}
+
+func (d *createdTable) ListTagsOfResourceWithContext(ctx aws.Context, req *dynamodb.ListTagsOfResourceInput, opts ...request.Option) (*dynamodb.ListTagsOfResourceOutput, error) {
+	// Return a tag per page, along with one added by AWS.
+	tags := append([]*dynamodb.Tag{{Key: aws.String("aws:cloudformation:stack-name"), Value: aws.String("stack")}}, d.tags...)
+	i := 0
+	if req.NextToken != nil {
+		i = int((*req.NextToken)[0] - '0')
+	}
+	resp := &dynamodb.ListTagsOfResourceOutput{Tags: tags[i : i+1]}
+	if i+1 < len(tags) {
+		resp.NextToken = aws.String(string(rune('0' + i + 1)))
+	}
+	return resp, nil
+}
+
+func TestShowCreateTable(t *testing.T) {
+	queries := []string{
+		`CREATE TABLE movies (title STRING HASH KEY, year NUMBER RANGE KEY, director STRING, rating NUMBER, ` +
+			`GLOBAL INDEX director_year HASH(director) RANGE(year) PROJECTION INCLUDE (title, actors) PROVISIONED THROUGHPUT READ 1 WRITE 2, ` +
+			`LOCAL INDEX title_rating RANGE(rating) PROJECTION KEYS_ONLY) PROVISIONED THROUGHPUT READ 3 WRITE 4`,
+		"CREATE TABLE `events.v2` (`range` NUMBER RANGE KEY, id BINARY HASH KEY, GLOBAL INDEX by_range HASH(`range`) PROJECTION ALL) " +
+			`BILLING MODE PAY_PER_REQUEST WITH (STREAM = NEW_AND_OLD_IMAGES, TTL = 'expires at', SSE = KMS, SSE_KMS_KEY = 'alias/events', ` +
+			`TABLE_CLASS = STANDARD_INFREQUENT_ACCESS, DELETION_PROTECTION = TRUE, TAGS = {team: 'payments', 'cost center': "it's"})`,
+	}
+	for _, query := range queries {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err, query)
+		req, ttl, tags, err := buildCreateTable(ast.CreateTable)
+		require.NoError(t, err)
+		// DynamoDB describes the hash key first, whatever the order of the attributes that created the table.
+		sort.SliceStable(req.KeySchema, func(i, j int) bool { return *req.KeySchema[i].KeyType == "HASH" })
+
+		stmt, err := PrepareShowCreateTable(&parser.ShowCreateTable{Table: *req.TableName})
+		require.NoError(t, err)
+		result, err := stmt.Show(context.Background(), &createdTable{req: req, ttl: ttl, tags: tags})
+		require.NoError(t, err)
+		require.Equal(t, []string{"name", "statement"}, result.Columns)
+		require.Equal(t, *req.TableName, result.Rows[0][0])
+
+		statement := result.Rows[0][1].(string)
+		t.Log(statement)
+		ast, err = parser.Parse(statement)
+		require.NoError(t, err, statement)
+		recreated, recreatedTTL, recreatedTags, err := buildCreateTable(ast.CreateTable)
+		require.NoError(t, err)
+		require.Equal(t, ttl, recreatedTTL)
+		require.Equal(t, tags, recreatedTags)
+		// The hash key attribute is moved before the range key, so that the hash key comes first in the key schema.
+		require.ElementsMatch(t, req.AttributeDefinitions, recreated.AttributeDefinitions)
+		recreated.AttributeDefinitions = req.AttributeDefinitions
+		require.Equal(t, req, recreated)
+	}
+}
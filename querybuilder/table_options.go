@@ -0,0 +1,135 @@
+package querybuilder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// applyTableOptions sets the fields of the CreateTable request from the WITH options of CREATE TABLE. The time to live
+// cannot be set when creating a table, so it is returned as a request to make once the table exists, as are the tags
+// to add with TagResource.
+func applyTableOptions(req *dynamodb.CreateTableInput, options []*parser.TableOption) (ttl *dynamodb.UpdateTimeToLiveInput, tags []*dynamodb.Tag, err error) {
+	seen := make(map[string]bool, len(options))
+	var kmsKey *string
+	for _, option := range options {
+		name := strings.ToUpper(option.Name)
+		if seen[name] {
+			return nil, nil, fmt.Errorf("option %s is set more than once", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "STREAM":
+			view, err := enumOption(option, append(dynamodb.StreamViewType_Values(), "NONE"))
+			if err != nil {
+				return nil, nil, err
+			}
+			if view != "NONE" {
+				req.StreamSpecification = &dynamodb.StreamSpecification{
+					StreamEnabled:  aws.Bool(true),
+					StreamViewType: aws.String(view),
+				}
+			}
+
+		case "TTL":
+			attr, err := stringOption(option)
+			if err != nil {
+				return nil, nil, err
+			}
+			ttl = &dynamodb.UpdateTimeToLiveInput{
+				TableName: req.TableName,
+				TimeToLiveSpecification: &dynamodb.TimeToLiveSpecification{
+					AttributeName: aws.String(attr),
+					Enabled:       aws.Bool(true),
+				},
+			}
+
+		case "SSE":
+			// DEFAULT encrypts with a key owned by DynamoDB, KMS with a key in the account.
+			typ, err := enumOption(option, []string{"DEFAULT", dynamodb.SSETypeKms})
+			if err != nil {
+				return nil, nil, err
+			}
+			req.SSESpecification = &dynamodb.SSESpecification{Enabled: aws.Bool(typ == dynamodb.SSETypeKms)}
+			if typ == dynamodb.SSETypeKms {
+				req.SSESpecification.SSEType = aws.String(typ)
+			}
+
+		case "SSE_KMS_KEY":
+			key, err := stringOption(option)
+			if err != nil {
+				return nil, nil, err
+			}
+			kmsKey = aws.String(key)
+
+		case "TABLE_CLASS":
+			class, err := enumOption(option, dynamodb.TableClass_Values())
+			if err != nil {
+				return nil, nil, err
+			}
+			req.TableClass = aws.String(class)
+
+		case "DELETION_PROTECTION":
+			if option.Value == nil || option.Value.Boolean == nil {
+				return nil, nil, fmt.Errorf("option %s must be TRUE or FALSE", name)
+			}
+			req.DeletionProtectionEnabled = aws.Bool(bool(*option.Value.Boolean))
+
+		case "TAGS":
+			if option.Value == nil || option.Value.Object == nil {
+				return nil, nil, fmt.Errorf("option %s must be an object, such as {team: 'payments'}", name)
+			}
+			for _, entry := range option.Value.Object.Entries {
+				if entry.Value.Str == nil {
+					return nil, nil, fmt.Errorf("value of tag %q must be a string", entry.Key)
+				}
+				tags = append(tags, &dynamodb.Tag{
+					Key:   aws.String(entry.Key),
+					Value: entry.Value.Str,
+				})
+			}
+
+		default:
+			return nil, nil, fmt.Errorf("unknown table option %s, expected one of STREAM, TTL, SSE, SSE_KMS_KEY, TABLE_CLASS, DELETION_PROTECTION or TAGS", option.Name)
+		}
+	}
+	if kmsKey != nil {
+		if req.SSESpecification == nil || req.SSESpecification.SSEType == nil {
+			return nil, nil, fmt.Errorf("option SSE_KMS_KEY requires SSE = KMS")
+		}
+		req.SSESpecification.KMSMasterKeyId = kmsKey
+	}
+	return ttl, tags, nil
+}
+
+// stringOption returns the value of an option that is a name or a string.
+func stringOption(option *parser.TableOption) (string, error) {
+	switch {
+	case option.Ident != nil:
+		return *option.Ident, nil
+	case option.Value != nil && option.Value.Str != nil:
+		return *option.Value.Str, nil
+	}
+	return "", fmt.Errorf("option %s must be a name or a string", strings.ToUpper(option.Name))
+}
+
+// enumOption returns the value of an option that must be one of the allowed values, ignoring case.
+func enumOption(option *parser.TableOption, allowed []string) (string, error) {
+	value, err := stringOption(option)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range allowed {
+		if strings.EqualFold(value, v) {
+			return v, nil
+		}
+	}
+	sort.Strings(allowed)
+	return "", fmt.Errorf("option %s must be one of %s, but was %s", strings.ToUpper(option.Name), strings.Join(allowed, ", "), value)
+}
@@ -0,0 +1,12 @@
+querybuilder.item{
+  Query: "SCAN * FROM gamescores",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      TableName: &"gamescores",
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
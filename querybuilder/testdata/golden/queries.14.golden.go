@@ -0,0 +1,39 @@
+querybuilder.item{
+  Query: "SCAN UserId, TopScore FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = :title AND TopScore > 1000",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      FilterExpression: &"GameTitle = :title AND TopScore > :_gen1",
+      IndexName: &"GameTitleIndex",
+      ProjectionExpression: &"UserId, TopScore",
+      TableName: &"gamescores",
+    },
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "UserId",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "TopScore",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{
+      ":title": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": 1000,
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,14 @@
+querybuilder.item{
+  Query: "SCAN * FROM movies LIMIT 5",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      Limit: &5,
+      TableName: &"movies",
+    },
+    Limit: 5,
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
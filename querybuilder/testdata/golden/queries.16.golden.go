@@ -0,0 +1,16 @@
+querybuilder.item{
+  Query: "SCAN * FROM gamescores WHERE TopScore > 1000 SEGMENTS 4",
+  Prepared: &querybuilder.PreparedQuery{
+    Scan: &dynamodb.ScanInput{
+      _: struct {}{      },
+      FilterExpression: &"TopScore > :_gen1",
+      TableName: &"gamescores",
+      TotalSegments: &4,
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": 1000,
+    },
+  },
+}
\ No newline at end of file
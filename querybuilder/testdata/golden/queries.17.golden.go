@@ -0,0 +1,32 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId IN (:a, :b, :c) AND GameTitle > \"G\" DESC LIMIT 10",
+  Prepared: &querybuilder.PreparedQuery{
+    Partitions: []*querybuilder.PartitionQuery{
+      {
+        Query: &dynamodb.QueryInput{
+          _: struct {}{          },
+          KeyConditionExpression: &"UserId = :_gen2 AND GameTitle > :_gen1",
+          Limit: &10,
+          ScanIndexForward: &false,
+          TableName: &"gamescores",
+        },
+        HashKeyParam: ":_gen2",
+        HashKeyValues: []string{
+          ":a",
+          ":b",
+          ":c",
+        },
+      },
+    },
+    Limit: 10,
+    NamedParams: querybuilder.NamedParams{
+      ":a": querybuilder.Empty{      },
+      ":b": querybuilder.Empty{      },
+      ":c": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "G",
+    },
+  },
+}
\ No newline at end of file
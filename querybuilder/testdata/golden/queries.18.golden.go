@@ -0,0 +1,38 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores WHERE UserId = ? AND Wins > ? OR UserId = ? AND begins_with(GameTitle, ?)",
+  Prepared: &querybuilder.PreparedQuery{
+    Partitions: []*querybuilder.PartitionQuery{
+      {
+        Query: &dynamodb.QueryInput{
+          _: struct {}{          },
+          FilterExpression: &"Wins > :_pos2",
+          KeyConditionExpression: &"UserId = :_pos1",
+          TableName: &"gamescores",
+        },
+        HashKeyParam: ":_pos1",
+        HashKeyValues: []string{
+          ":_pos1",
+        },
+      },
+      {
+        Query: &dynamodb.QueryInput{
+          _: struct {}{          },
+          KeyConditionExpression: &"UserId = :_pos3 AND begins_with(GameTitle, :_pos4)",
+          TableName: &"gamescores",
+        },
+        HashKeyParam: ":_pos3",
+        HashKeyValues: []string{
+          ":_pos3",
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{
+      1: ":_pos1",
+      2: ":_pos2",
+      3: ":_pos3",
+      4: ":_pos4",
+    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,70 @@
+querybuilder.item{
+  Query: "SELECT TopScore, `Top.Wins` FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle IN (\"Meteor Blasters\", \"Galaxy Invaders\") OR GameTitle = \"Attack Ships\" AND size > 3",
+  Prepared: &querybuilder.PreparedQuery{
+    Partitions: []*querybuilder.PartitionQuery{
+      {
+        Query: &dynamodb.QueryInput{
+          _: struct {}{          },
+          ExpressionAttributeNames: map[string]*string{
+            "#_gen1": &"Top.Wins",
+          },
+          IndexName: &"GameTitleIndex",
+          KeyConditionExpression: &"GameTitle = :_gen5",
+          ProjectionExpression: &"TopScore, #_gen1",
+          TableName: &"gamescores",
+        },
+        HashKeyParam: ":_gen5",
+        HashKeyValues: []string{
+          ":_gen1",
+          ":_gen2",
+        },
+      },
+      {
+        Query: &dynamodb.QueryInput{
+          _: struct {}{          },
+          ExpressionAttributeNames: map[string]*string{
+            "#_gen1": &"Top.Wins",
+            "#size": &"size",
+          },
+          FilterExpression: &"#size > :_gen4",
+          IndexName: &"GameTitleIndex",
+          KeyConditionExpression: &"GameTitle = :_gen3",
+          ProjectionExpression: &"TopScore, #_gen1",
+          TableName: &"gamescores",
+        },
+        HashKeyParam: ":_gen3",
+        HashKeyValues: []string{
+          ":_gen3",
+        },
+      },
+    },
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "TopScore",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "Top.Wins",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{
+      ":_gen1": "Meteor Blasters",
+      ":_gen2": "Galaxy Invaders",
+      ":_gen3": "Attack Ships",
+      ":_gen4": 3,
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,45 @@
+querybuilder.item{
+  Query: "SELECT title, year FROM movies WHERE title = :title AND year = :year",
+  Prepared: &querybuilder.PreparedQuery{
+    GetItem: &dynamodb.GetItemInput{
+      _: struct {}{      },
+      ExpressionAttributeNames: map[string]*string{
+        "#year": &"year",
+      },
+      ProjectionExpression: &"title, #year",
+      TableName: &"movies",
+    },
+    Keys: []map[string]string{
+      map[string]string{
+        "title": ":title",
+        "year": ":year",
+      },
+    },
+    Columns: []*parser.ProjectionColumn{
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "title",
+            },
+          },
+        },
+      },
+      {
+        DocumentPath: &parser.DocumentPath{
+          Fragment: []*parser.PathFragment{
+            {
+              Symbol: "year",
+            },
+          },
+        },
+      },
+    },
+    NamedParams: querybuilder.NamedParams{
+      ":title": querybuilder.Empty{      },
+      ":year": querybuilder.Empty{      },
+    },
+    PositionalParams: map[int]string{    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,32 @@
+querybuilder.item{
+  Query: "SELECT * FROM movies WHERE (title, year) IN ((?, ?), (?, ?)) LIMIT 1",
+  Prepared: &querybuilder.PreparedQuery{
+    BatchGet: &dynamodb.BatchGetItemInput{
+      _: struct {}{      },
+      RequestItems: map[string]*dynamodb.KeysAndAttributes{
+        "movies": &dynamodb.KeysAndAttributes{
+          _: struct {}{          },
+        },
+      },
+    },
+    Keys: []map[string]string{
+      map[string]string{
+        "title": ":_pos1",
+        "year": ":_pos2",
+      },
+      map[string]string{
+        "title": ":_pos3",
+        "year": ":_pos4",
+      },
+    },
+    Limit: 1,
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{
+      1: ":_pos1",
+      2: ":_pos2",
+      3: ":_pos3",
+      4: ":_pos4",
+    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
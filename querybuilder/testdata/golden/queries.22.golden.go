@@ -0,0 +1,17 @@
+querybuilder.item{
+  Query: "SELECT * FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = ? AND TopScore = ?",
+  Prepared: &querybuilder.PreparedQuery{
+    Query: &dynamodb.QueryInput{
+      _: struct {}{      },
+      IndexName: &"GameTitleIndex",
+      KeyConditionExpression: &"GameTitle = :_pos1 AND TopScore = :_pos2",
+      TableName: &"gamescores",
+    },
+    NamedParams: querybuilder.NamedParams{    },
+    PositionalParams: map[int]string{
+      1: ":_pos1",
+      2: ":_pos2",
+    },
+    FixedParams: map[string]interface {}{    },
+  },
+}
\ No newline at end of file
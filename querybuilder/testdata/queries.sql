@@ -20,3 +20,20 @@ SELECT * FROM gamescores WHERE UserId = ? AND TopScore > ?
 -- positional placeholders (?) when key expression appears after filter expression
 -- https://github.com/mightyguava/dynamosql/issues/1
 SELECT * FROM gamescores WHERE TopScore > ? AND UserId = ?
+-- SCAN the whole table
+SCAN * FROM gamescores
+-- SCAN with a filter on key attributes
+SCAN UserId, TopScore FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = :title AND TopScore > 1000
+-- LIMIT is pushed down when there is no filter
+SCAN * FROM movies LIMIT 5
+-- Parallel SCAN
+SCAN * FROM gamescores WHERE TopScore > 1000 SEGMENTS 4
+SELECT * FROM gamescores WHERE UserId IN (:a, :b, :c) AND GameTitle > "G" DESC LIMIT 10
+SELECT * FROM gamescores WHERE UserId = ? AND Wins > ? OR UserId = ? AND begins_with(GameTitle, ?)
+SELECT TopScore, `Top.Wins` FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle IN ("Meteor Blasters", "Galaxy Invaders") OR GameTitle = "Attack Ships" AND size > 3
+-- Full primary key uses GetItem
+SELECT title, year FROM movies WHERE title = :title AND year = :year
+-- Multiple primary keys use BatchGetItem
+SELECT * FROM movies WHERE (title, year) IN ((?, ?), (?, ?)) LIMIT 1
+-- Indexes do not support GetItem
+SELECT * FROM gamescores USE INDEX (GameTitleIndex) WHERE GameTitle = ? AND TopScore = ?
@@ -4,7 +4,7 @@ SELECT * FROM gamescores
 SELECT * FROM gamescores WHERE Wins = 3
 -- $ numbered placeholder is not allowed
 SELECT * FROM gamescores WHERE UserId = "101" AND Wins = $1
--- OR may not appear at the top level
+-- Each top-level OR branch must match the partition key
 SELECT * FROM gamescores WHERE UserId = :UserId OR Wins = 3
 -- Partition key may not appear in a nested expression
 SELECT * FROM gamescores WHERE UserId = :UserId AND (Wins = 3 OR UserId = "105")
@@ -23,4 +23,18 @@ SELECT * FROM gamescores WHERE UserId = :UserId AND attribute_exists(GameTitle)
 -- Mix ? and : placeholders
 SELECT * FROM gamescores WHERE UserId = :UserId AND Wins = ?
 -- Use JSON in query
-SELECT * FROM gamescores WHERE UserId = {id: 10}
\ No newline at end of file
+SELECT * FROM gamescores WHERE UserId = {id: 10}
+-- SCAN has no ordering
+SCAN * FROM gamescores DESC
+-- SEGMENTS requires SCAN
+SELECT * FROM gamescores WHERE UserId = :UserId SEGMENTS 4
+-- SEGMENTS must be positive
+SCAN * FROM gamescores SEGMENTS 0
+-- Partition key may not appear twice with IN
+SELECT * FROM gamescores WHERE UserId IN (:a, :b) AND UserId = :c
+-- Tuple IN may not be combined with other conditions
+SELECT * FROM gamescores WHERE (UserId, GameTitle) IN ((?, ?)) AND Wins > 5
+-- Tuple IN must match the primary key
+SELECT * FROM gamescores WHERE (UserId, Wins) IN ((?, ?))
+-- Tuple IN values must match the attributes
+SELECT * FROM gamescores WHERE (UserId, GameTitle) IN ((?, ?), (?))
\ No newline at end of file
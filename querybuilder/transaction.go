@@ -0,0 +1,78 @@
+package querybuilder
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// maxTransactItems is the maximum number of items DynamoDB allows in a transaction.
+const maxTransactItems = 100
+
+var errReturningInTransaction = errors.New("RETURNING is not supported in transactions")
+
+var (
+	_ TransactStmt = &PreparedInsert{}
+	_ TransactStmt = &PreparedUpdate{}
+	_ TransactStmt = &PreparedDelete{}
+	_ TransactStmt = &PreparedCheck{}
+)
+
+// TransactStmt is implemented by statements that can be executed as part of a Transaction.
+type TransactStmt interface {
+	// Transact buffers the writes of the statement in the transaction, instead of executing them. The returned result
+	// assumes the writes will succeed when the transaction is committed.
+	Transact(tx *Transaction, args []driver.NamedValue) (*DriverResult, error)
+}
+
+// Transaction buffers writes to be submitted atomically with TransactWriteItems.
+type Transaction struct {
+	token string
+	items []*dynamodb.TransactWriteItem
+	keys  map[string]bool
+}
+
+func NewTransaction() (*Transaction, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	return &Transaction{
+		token: hex.EncodeToString(token),
+		keys:  make(map[string]bool),
+	}, nil
+}
+
+// add buffers a write to the item with the given key. It fails if the transaction is full, or already writes to the
+// item, since DynamoDB would reject the transaction on commit.
+func (t *Transaction) add(table string, key map[string]*dynamodb.AttributeValue, item *dynamodb.TransactWriteItem) error {
+	if len(t.items) >= maxTransactItems {
+		return fmt.Errorf("a transaction may contain at most %d writes", maxTransactItems)
+	}
+	id := fmt.Sprintf("%q:%s", table, keyString(key))
+	if t.keys[id] {
+		return fmt.Errorf("a transaction may only write to an item once, but item %s in table %q is written twice", keyString(key), table)
+	}
+	t.keys[id] = true
+	t.items = append(t.items, item)
+	return nil
+}
+
+// Commit submits the buffered writes. The ClientRequestToken makes the commit idempotent if it is retried.
+func (t *Transaction) Commit(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI) error {
+	if len(t.items) == 0 {
+		return nil
+	}
+	_, err := dynamo.TransactWriteItemsWithContext(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems:      t.items,
+		ClientRequestToken: aws.String(t.token),
+	})
+	return err
+}
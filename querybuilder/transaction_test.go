@@ -0,0 +1,132 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+type recordTransactWrite struct {
+	dynamodbiface.DynamoDBAPI
+	reqs []*dynamodb.TransactWriteItemsInput
+}
+
+func (d *recordTransactWrite) TransactWriteItemsWithContext(ctx aws.Context, req *dynamodb.TransactWriteItemsInput, opts ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+	d.reqs = append(d.reqs, req)
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+func TestTransaction(t *testing.T) {
+	movies := schema.NewTableFromCreate(fixtures.Movies.Create)
+	prepareUpdateStmt := func(query string) *PreparedUpdate {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		prepared, err := prepareUpdate(movies, ast.Update)
+		require.NoError(t, err)
+		return prepared
+	}
+	prepareDeleteStmt := func(query string) *PreparedDelete {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		prepared, err := prepareDelete(movies, ast.Delete)
+		require.NoError(t, err)
+		return prepared
+	}
+	insert := &PreparedInsert{Table: movies}
+	update := prepareUpdateStmt(`UPDATE movies SET rating = ? WHERE title = ? AND year = ?`)
+	del := prepareDeleteStmt(`DELETE FROM movies WHERE title = ? AND year = ?`)
+
+	t.Run("commit", func(t *testing.T) {
+		tx, err := NewTransaction()
+		require.NoError(t, err)
+		result, err := insert.Transact(tx, []driver.NamedValue{{Value: `{"title": "Big", "year": 1988}`}})
+		require.NoError(t, err)
+		require.Equal(t, &DriverResult{count: 1}, result)
+		_, err = update.Transact(tx, []driver.NamedValue{
+			{Ordinal: 1, Value: int64(5)},
+			{Ordinal: 2, Value: "Heat"},
+			{Ordinal: 3, Value: int64(1995)},
+		})
+		require.NoError(t, err)
+
+		dynamo := &recordTransactWrite{}
+		require.NoError(t, tx.Commit(context.Background(), dynamo))
+		require.Len(t, dynamo.reqs, 1)
+		req := dynamo.reqs[0]
+		require.Len(t, *req.ClientRequestToken, 32)
+		require.Equal(t, []*dynamodb.TransactWriteItem{
+			{
+				Put: &dynamodb.Put{
+					TableName:           aws.String("movies"),
+					ConditionExpression: aws.String("attribute_not_exists(title)"),
+					Item: map[string]*dynamodb.AttributeValue{
+						"title": {S: aws.String("Big")},
+						"year":  {N: aws.String("1988")},
+					},
+				},
+			},
+			{
+				Update: &dynamodb.Update{
+					TableName:           aws.String("movies"),
+					UpdateExpression:    aws.String("SET rating = :_pos1"),
+					ConditionExpression: aws.String("attribute_exists(title)"),
+					Key: map[string]*dynamodb.AttributeValue{
+						"title": {S: aws.String("Heat")},
+						"year":  {N: aws.String("1995")},
+					},
+					ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+						":_pos1": {N: aws.String("5")},
+					},
+				},
+			},
+		}, req.TransactItems)
+	})
+
+	t.Run("same item written twice", func(t *testing.T) {
+		tx, err := NewTransaction()
+		require.NoError(t, err)
+		_, err = insert.Transact(tx, []driver.NamedValue{{Value: `{"title": "Big", "year": 1988}`}})
+		require.NoError(t, err)
+		_, err = del.Transact(tx, []driver.NamedValue{
+			{Ordinal: 1, Value: "Big"},
+			{Ordinal: 2, Value: int64(1988)},
+		})
+		require.EqualError(t, err, `a transaction may only write to an item once, but item title="Big", year=1988 in table "movies" is written twice`)
+	})
+
+	t.Run("too many items", func(t *testing.T) {
+		tx, err := NewTransaction()
+		require.NoError(t, err)
+		for i := 0; i < maxTransactItems; i++ {
+			_, err = del.Transact(tx, []driver.NamedValue{
+				{Ordinal: 1, Value: fmt.Sprintf("movie %d", i)},
+				{Ordinal: 2, Value: int64(2000)},
+			})
+			require.NoError(t, err)
+		}
+		_, err = del.Transact(tx, []driver.NamedValue{
+			{Ordinal: 1, Value: "one too many"},
+			{Ordinal: 2, Value: int64(2000)},
+		})
+		require.EqualError(t, err, "a transaction may contain at most 100 writes")
+	})
+
+	t.Run("returning not allowed", func(t *testing.T) {
+		tx, err := NewTransaction()
+		require.NoError(t, err)
+		returning := prepareDeleteStmt(`DELETE FROM movies WHERE title = "Big" AND year = 1988 RETURNING ALL_OLD`)
+		_, err = returning.Transact(tx, nil)
+		require.Equal(t, errReturningInTransaction, err)
+	})
+}
@@ -0,0 +1,318 @@
+package querybuilder
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/repr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+type PreparedUpdate struct {
+	Update           *dynamodb.UpdateItemInput
+	Key              map[string]string
+	NamedParams      NamedParams
+	PositionalParams map[int]string
+	FixedParams      map[string]interface{}
+}
+
+func PrepareUpdate(ctx context.Context, tables *schema.TableLoader, ast *parser.AST) (*PreparedUpdate, error) {
+	upd := ast.Update
+	if upd == nil {
+		return nil, fmt.Errorf("expected UPDATE but got %s", repr.String(ast))
+	}
+	table, err := tables.Get(ctx, upd.Table)
+	if err != nil {
+		return nil, err
+	}
+	return prepareUpdate(table, upd)
+}
+
+func prepareUpdate(table *schema.Table, upd *parser.Update) (*PreparedUpdate, error) {
+	if len(upd.Set) == 0 && len(upd.Remove) == 0 && len(upd.Add) == 0 && len(upd.Delete) == 0 {
+		return nil, errors.New("UPDATE requires a SET, REMOVE, ADD or DELETE clause")
+	}
+	if err := checkUpdateActions(upd); err != nil {
+		return nil, err
+	}
+	ctx := NewContext(table, "")
+	if err := prepareValuesAndPlaceholders(ctx, upd); err != nil {
+		return nil, err
+	}
+	if len(ctx.PositionalParams) > 0 && len(ctx.NamedParams) > 0 {
+		return nil, errors.New("cannot mix positional params (?) with named params (:param)")
+	}
+	key, cond, err := extractPrimaryKey(ctx, upd.Where)
+	if err != nil {
+		return nil, err
+	}
+	updateExpr, err := buildUpdateExpression(ctx, upd)
+	if err != nil {
+		return nil, err
+	}
+	conditionExpr, err := buildConditionExpression(ctx, cond)
+	if err != nil {
+		return nil, err
+	}
+	// UPDATE only modifies existing items, rather than creating them as UpdateItem does by default.
+	conditionExpr = requireExists(ctx, conditionExpr)
+
+	req := &dynamodb.UpdateItemInput{
+		TableName:                &upd.Table,
+		UpdateExpression:         aws.String(updateExpr),
+		ConditionExpression:      aws.String(conditionExpr),
+		ExpressionAttributeNames: ctx.ExpressionAttributeNames(),
+		ReturnValues:             upd.Returning,
+	}
+	return &PreparedUpdate{
+		Update:           req,
+		Key:              key,
+		NamedParams:      ctx.NamedParams,
+		PositionalParams: ctx.PositionalParams,
+		FixedParams:      ctx.FixedParams,
+	}, nil
+}
+
+func buildUpdateExpression(ctx *Context, upd *parser.Update) (string, error) {
+	v := &visitor{Context: ctx}
+	var clauses []string
+	if len(upd.Set) > 0 {
+		sets := make([]string, 0, len(upd.Set))
+		for _, set := range upd.Set {
+			if ctx.IsKey(set.Path.String()) {
+				return "", fmt.Errorf("key attribute %q may not be updated", set.Path)
+			}
+			value := buildSetOperand(v, &parser.SetOperand{Value: set.Value, Function: set.Function, Path: set.Operand})
+			if set.Arithmetic != nil {
+				value += " " + set.Arithmetic.Operator + " " + buildSetOperand(v, set.Arithmetic.Operand)
+			}
+			sets = append(sets, ctx.BuildPath(set.Path)+" = "+value)
+		}
+		clauses = append(clauses, "SET "+strings.Join(sets, ", "))
+	}
+	if len(upd.Remove) > 0 {
+		removes := make([]string, 0, len(upd.Remove))
+		for _, path := range upd.Remove {
+			if ctx.IsKey(path.String()) {
+				return "", fmt.Errorf("key attribute %q may not be removed", path)
+			}
+			removes = append(removes, ctx.BuildPath(path))
+		}
+		clauses = append(clauses, "REMOVE "+strings.Join(removes, ", "))
+	}
+	for _, action := range []struct {
+		name   string
+		values []*parser.PathValue
+	}{{"ADD", upd.Add}, {"DELETE", upd.Delete}} {
+		if len(action.values) == 0 {
+			continue
+		}
+		values := make([]string, 0, len(action.values))
+		for _, value := range action.values {
+			if ctx.IsKey(value.Path.String()) {
+				return "", fmt.Errorf("key attribute %q may not be updated", value.Path)
+			}
+			values = append(values, ctx.BuildPath(value.Path)+" "+v.VisitSimpleExpression(value.Value))
+		}
+		clauses = append(clauses, action.name+" "+strings.Join(values, ", "))
+	}
+	return strings.Join(clauses, " "), nil
+}
+
+func buildSetOperand(v *visitor, operand *parser.SetOperand) string {
+	switch {
+	case operand.Value != nil:
+		return v.VisitSimpleExpression(operand.Value)
+	case operand.Function != nil:
+		args := make([]string, 0, len(operand.Function.Args))
+		for _, arg := range operand.Function.Args {
+			args = append(args, buildSetOperand(v, arg))
+		}
+		return operand.Function.Function + "(" + strings.Join(args, ", ") + ")"
+	default:
+		return v.BuildPath(operand.Path)
+	}
+}
+
+// checkUpdateActions checks the actions that DynamoDB would otherwise only reject when the statement is executed.
+// It must be called before values are replaced by placeholders, since literals are checked too.
+func checkUpdateActions(upd *parser.Update) error {
+	var paths []*parser.DocumentPath
+	for _, set := range upd.Set {
+		paths = append(paths, set.Path)
+		first := &parser.SetOperand{Value: set.Value, Function: set.Function, Path: set.Operand}
+		if err := checkSetOperand(first); err != nil {
+			return err
+		}
+		arithmetic := set.Arithmetic
+		if arithmetic == nil {
+			continue
+		}
+		if arithmetic.Signed != nil {
+			// Split the sign from the number, so views+1 is the same as views + 1.
+			signed := *arithmetic.Signed
+			if signed[0] != '+' && signed[0] != '-' {
+				return fmt.Errorf("expected + or - before %s in SET %s", signed, set.Path)
+			}
+			number, err := strconv.ParseFloat(signed[1:], 64)
+			if err != nil {
+				return err
+			}
+			arithmetic.Operator = signed[:1]
+			arithmetic.Operand = &parser.SetOperand{Value: &parser.Value{Scalar: parser.Scalar{Number: &number}}}
+			arithmetic.Signed = nil
+		}
+		if err := checkSetOperand(arithmetic.Operand); err != nil {
+			return err
+		}
+		for _, operand := range []*parser.SetOperand{first, arithmetic.Operand} {
+			if isLiteral(operand.Value) && operand.Value.Number == nil {
+				return fmt.Errorf("cannot add or subtract %s in SET %s", operand.Value, set.Path)
+			}
+			if operand.Function != nil && operand.Function.Function == "list_append" {
+				return fmt.Errorf("cannot add or subtract a list in SET %s", set.Path)
+			}
+		}
+	}
+	paths = append(paths, upd.Remove...)
+	for _, add := range upd.Add {
+		if err := checkTopLevel("ADD", add.Path); err != nil {
+			return err
+		}
+		if isLiteral(add.Value) && add.Value.Number == nil {
+			return fmt.Errorf("ADD requires a number or a set, not %s", add.Value)
+		}
+		paths = append(paths, add.Path)
+	}
+	for _, del := range upd.Delete {
+		if err := checkTopLevel("DELETE", del.Path); err != nil {
+			return err
+		}
+		if isLiteral(del.Value) {
+			return fmt.Errorf("DELETE requires a set, not %s", del.Value)
+		}
+		paths = append(paths, del.Path)
+	}
+
+	// DynamoDB rejects updates to the same path, or to a path and a path within it.
+	for i, a := range paths {
+		for _, b := range paths[:i] {
+			if pathsOverlap(a.String(), b.String()) {
+				if a.String() == b.String() {
+					return fmt.Errorf("path %s is updated more than once", a)
+				}
+				return fmt.Errorf("paths %s and %s overlap, so they cannot be updated together", b, a)
+			}
+		}
+	}
+	return nil
+}
+
+func checkSetOperand(operand *parser.SetOperand) error {
+	fn := operand.Function
+	if fn == nil {
+		return nil
+	}
+	switch fn.Function {
+	case "list_append":
+	case "if_not_exists":
+		if len(fn.Args) == 2 && fn.Args[0].Path == nil {
+			return errors.New("the first argument of if_not_exists must be a path")
+		}
+	default:
+		return fmt.Errorf("unsupported function %q in SET, expected list_append or if_not_exists", fn.Function)
+	}
+	if len(fn.Args) != 2 {
+		return fmt.Errorf("%s takes 2 arguments, got %d", fn.Function, len(fn.Args))
+	}
+	for _, arg := range fn.Args {
+		if err := checkSetOperand(arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTopLevel checks that the path is a top-level attribute, since ADD and DELETE cannot be used on nested
+// attributes.
+func checkTopLevel(action string, path *parser.DocumentPath) error {
+	if len(path.Fragment) > 1 || len(path.Fragment[0].Indexes) > 0 {
+		return fmt.Errorf("%s can only be used on top-level attributes, not %s", action, path)
+	}
+	return nil
+}
+
+// isLiteral returns true if the value is written in the statement, rather than bound to a placeholder.
+func isLiteral(value *parser.Value) bool {
+	return value != nil && value.PlaceHolder == nil && !value.PositionalPlaceholder
+}
+
+// pathsOverlap returns true if the paths are the same, or one is within the other.
+func pathsOverlap(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return a == b || strings.HasPrefix(b, a) && (b[len(a)] == '.' || b[len(a)] == '[')
+}
+
+func (p *PreparedUpdate) NewRequest(args []driver.NamedValue) (*dynamodb.UpdateItemInput, error) {
+	values, err := bindArgs(p.FixedParams, p.NamedParams, p.PositionalParams, args)
+	if err != nil {
+		return nil, err
+	}
+	req := *p.Update
+	req.Key = bindKey(p.Key, values)
+	req.ExpressionAttributeValues = usedValues(values, req.UpdateExpression, req.ConditionExpression)
+	return &req, nil
+}
+
+func (p *PreparedUpdate) Do(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, args []driver.NamedValue) (*DriverResult, error) {
+	req, err := p.NewRequest(args)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := dynamo.UpdateItemWithContext(ctx, req)
+	if isConditionalCheckFailed(err) {
+		// The item does not exist or did not match the WHERE clause.
+		return &DriverResult{count: 0}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &DriverResult{
+		count:    1,
+		returned: resp.Attributes,
+	}, nil
+}
+
+func (p *PreparedUpdate) Transact(tx *Transaction, args []driver.NamedValue) (*DriverResult, error) {
+	req, err := p.NewRequest(args)
+	if err != nil {
+		return nil, err
+	}
+	if req.ReturnValues != nil && *req.ReturnValues != dynamodb.ReturnValueNone {
+		return nil, errReturningInTransaction
+	}
+	err = tx.add(*req.TableName, req.Key, &dynamodb.TransactWriteItem{
+		Update: &dynamodb.Update{
+			TableName:                 req.TableName,
+			Key:                       req.Key,
+			UpdateExpression:          req.UpdateExpression,
+			ConditionExpression:       req.ConditionExpression,
+			ExpressionAttributeNames:  req.ExpressionAttributeNames,
+			ExpressionAttributeValues: req.ExpressionAttributeValues,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &DriverResult{count: 1}, nil
+}
@@ -0,0 +1,253 @@
+package querybuilder
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+	"github.com/mightyguava/dynamosql/testing/fixtures"
+)
+
+func TestPrepareUpdate(t *testing.T) {
+	table := schema.NewTableFromCreate(fixtures.Movies.Create)
+	tests := []struct {
+		name     string
+		query    string
+		args     []driver.NamedValue
+		expected *dynamodb.UpdateItemInput
+	}{
+		{
+			name:  "set and remove",
+			query: `UPDATE movies SET rating = :rating, info.actors[2] = 'Tom Hanks' REMOVE plot WHERE title = :title AND year = :year`,
+			args: []driver.NamedValue{
+				{Name: "rating", Value: 7.5},
+				{Name: "title", Value: "Big"},
+				{Name: "year", Value: int64(1988)},
+			},
+			expected: &dynamodb.UpdateItemInput{
+				TableName:           aws.String("movies"),
+				UpdateExpression:    aws.String("SET rating = :rating, info.actors[2] = :_gen1 REMOVE plot"),
+				ConditionExpression: aws.String("attribute_exists(title)"),
+				Key: map[string]*dynamodb.AttributeValue{
+					"title": {S: aws.String("Big")},
+					"year":  {N: aws.String("1988")},
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":rating": {N: aws.String("7.5")},
+					":_gen1":  {S: aws.String("Tom Hanks")},
+				},
+			},
+		},
+		{
+			name:  "condition and returning",
+			query: `UPDATE movies SET rating = ? WHERE title = ? AND year = ? AND (rating < ? OR attribute_not_exists(rating)) RETURNING ALL_NEW`,
+			args: []driver.NamedValue{
+				{Ordinal: 1, Value: int64(5)},
+				{Ordinal: 2, Value: "Big"},
+				{Ordinal: 3, Value: int64(1988)},
+				{Ordinal: 4, Value: int64(5)},
+			},
+			expected: &dynamodb.UpdateItemInput{
+				TableName:           aws.String("movies"),
+				UpdateExpression:    aws.String("SET rating = :_pos1"),
+				ConditionExpression: aws.String("attribute_exists(title) AND (rating < :_pos4 OR attribute_not_exists(rating))"),
+				Key: map[string]*dynamodb.AttributeValue{
+					"title": {S: aws.String("Big")},
+					"year":  {N: aws.String("1988")},
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":_pos1": {N: aws.String("5")},
+					":_pos4": {N: aws.String("5")},
+				},
+				ReturnValues: aws.String("ALL_NEW"),
+			},
+		},
+		{
+			name:  "placeholder shared by key and condition",
+			query: `UPDATE movies SET info.plot = :plot WHERE title = :title AND year = :year AND info.plot = :title`,
+			args: []driver.NamedValue{
+				{Name: "plot", Value: "Fire"},
+				{Name: "title", Value: "Big"},
+				{Name: "year", Value: int64(1988)},
+			},
+			expected: &dynamodb.UpdateItemInput{
+				TableName:           aws.String("movies"),
+				UpdateExpression:    aws.String("SET info.plot = :plot"),
+				ConditionExpression: aws.String("attribute_exists(title) AND info.plot = :title"),
+				Key: map[string]*dynamodb.AttributeValue{
+					"title": {S: aws.String("Big")},
+					"year":  {N: aws.String("1988")},
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":plot":  {S: aws.String("Fire")},
+					":title": {S: aws.String("Big")},
+				},
+			},
+		},
+		{
+			name:  "arithmetic and functions",
+			query: `UPDATE movies SET views = views + :n, rank = rank-1, info.genres = list_append(if_not_exists(info.genres, :empty), :genres), created = if_not_exists(created, :now) WHERE title = :title AND year = :year`,
+			args: []driver.NamedValue{
+				{Name: "n", Value: int64(1)},
+				{Name: "empty", Value: &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}},
+				{Name: "genres", Value: &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{{S: aws.String("comedy")}}}},
+				{Name: "now", Value: int64(1600000000)},
+				{Name: "title", Value: "Big"},
+				{Name: "year", Value: int64(1988)},
+			},
+			expected: &dynamodb.UpdateItemInput{
+				TableName:           aws.String("movies"),
+				UpdateExpression:    aws.String("SET #views = #views + :n, #rank = #rank - :_gen1, info.genres = list_append(if_not_exists(info.genres, :empty), :genres), created = if_not_exists(created, :now)"),
+				ConditionExpression: aws.String("attribute_exists(title)"),
+				ExpressionAttributeNames: map[string]*string{
+					"#views": aws.String("views"),
+					"#rank":  aws.String("rank"),
+				},
+				Key: map[string]*dynamodb.AttributeValue{
+					"title": {S: aws.String("Big")},
+					"year":  {N: aws.String("1988")},
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":n":      {N: aws.String("1")},
+					":_gen1":  {N: aws.String("1")},
+					":empty":  {L: []*dynamodb.AttributeValue{}},
+					":genres": {L: []*dynamodb.AttributeValue{{S: aws.String("comedy")}}},
+					":now":    {N: aws.String("1600000000")},
+				},
+			},
+		},
+		{
+			name:  "add and delete",
+			query: `UPDATE movies SET rating = 5 ADD visitors ?, views 1 DELETE editors ? WHERE title = ? AND year = ?`,
+			args: []driver.NamedValue{
+				{Ordinal: 1, Value: &dynamodb.AttributeValue{SS: aws.StringSlice([]string{"alice"})}},
+				{Ordinal: 2, Value: &dynamodb.AttributeValue{SS: aws.StringSlice([]string{"bob"})}},
+				{Ordinal: 3, Value: "Big"},
+				{Ordinal: 4, Value: int64(1988)},
+			},
+			expected: &dynamodb.UpdateItemInput{
+				TableName:           aws.String("movies"),
+				UpdateExpression:    aws.String("SET rating = :_gen1 ADD visitors :_pos1, #views :_gen2 DELETE editors :_pos2"),
+				ConditionExpression: aws.String("attribute_exists(title)"),
+				ExpressionAttributeNames: map[string]*string{
+					"#views": aws.String("views"),
+				},
+				Key: map[string]*dynamodb.AttributeValue{
+					"title": {S: aws.String("Big")},
+					"year":  {N: aws.String("1988")},
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":_gen1": {N: aws.String("5")},
+					":_gen2": {N: aws.String("1")},
+					":_pos1": {SS: aws.StringSlice([]string{"alice"})},
+					":_pos2": {SS: aws.StringSlice([]string{"bob"})},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ast, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			prepared, err := prepareUpdate(table, ast.Update)
+			require.NoError(t, err)
+			req, err := prepared.NewRequest(test.args)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, req)
+		})
+	}
+}
+
+func TestPrepareUpdateInvalid(t *testing.T) {
+	table := schema.NewTableFromCreate(fixtures.Movies.Create)
+	tests := []struct {
+		name  string
+		query string
+		err   string
+	}{
+		{
+			name:  "missing sort key",
+			query: `UPDATE movies SET rating = 5 WHERE title = "Big"`,
+			err:   "partition key and sort key must appear in the WHERE clause",
+		},
+		{
+			name:  "key in range condition",
+			query: `UPDATE movies SET rating = 5 WHERE title = "Big" AND year > 1988`,
+			err:   "partition key and sort key must appear in the WHERE clause",
+		},
+		{
+			name:  "update key",
+			query: `UPDATE movies SET year = 1989 WHERE title = "Big" AND year = 1988`,
+			err:   `key attribute "year" may not be updated`,
+		},
+		{
+			name:  "no actions",
+			query: `UPDATE movies WHERE title = "Big" AND year = 1988`,
+			err:   "UPDATE requires a SET, REMOVE, ADD or DELETE clause",
+		},
+		{
+			name:  "add to nested path",
+			query: `UPDATE movies ADD info.visitors :visitors WHERE title = "Big" AND year = 1988`,
+			err:   "ADD can only be used on top-level attributes, not info.visitors",
+		},
+		{
+			name:  "add string",
+			query: `UPDATE movies ADD visitors "alice" WHERE title = "Big" AND year = 1988`,
+			err:   `ADD requires a number or a set, not "alice"`,
+		},
+		{
+			name:  "delete number",
+			query: `UPDATE movies DELETE visitors 1 WHERE title = "Big" AND year = 1988`,
+			err:   "DELETE requires a set, not 1",
+		},
+		{
+			name:  "same path twice",
+			query: `UPDATE movies SET rating = 5 ADD rating 1 WHERE title = "Big" AND year = 1988`,
+			err:   "path rating is updated more than once",
+		},
+		{
+			name:  "overlapping paths",
+			query: `UPDATE movies SET info.rating = 5 REMOVE info WHERE title = "Big" AND year = 1988`,
+			err:   "paths info.rating and info overlap, so they cannot be updated together",
+		},
+		{
+			name:  "unsupported function",
+			query: `UPDATE movies SET rating = size(info) WHERE title = "Big" AND year = 1988`,
+			err:   `unsupported function "size" in SET, expected list_append or if_not_exists`,
+		},
+		{
+			name:  "if_not_exists of a value",
+			query: `UPDATE movies SET rating = if_not_exists(:a, :b) WHERE title = "Big" AND year = 1988`,
+			err:   "the first argument of if_not_exists must be a path",
+		},
+		{
+			name:  "add a string",
+			query: `UPDATE movies SET rating = rating + "one" WHERE title = "Big" AND year = 1988`,
+			err:   `cannot add or subtract "one" in SET rating`,
+		},
+		{
+			name:  "missing operator",
+			query: `UPDATE movies SET rating = rating 1 WHERE title = "Big" AND year = 1988`,
+			err:   "expected + or - before 1 in SET rating",
+		},
+		{
+			name:  "mixed placeholders",
+			query: `UPDATE movies SET rating = ? WHERE title = :title AND year = 1988`,
+			err:   "cannot mix positional params (?) with named params (:param)",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ast, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			_, err = prepareUpdate(table, ast.Update)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.err)
+		})
+	}
+}
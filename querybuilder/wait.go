@@ -0,0 +1,64 @@
+package querybuilder
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// The delay between checks of a table's status while waiting for it.
+var tableWaitDelay = time.Second
+
+// waitForTable polls DescribeTable until the table and all of its global secondary indexes are ACTIVE, or if deleted
+// is true, until the table no longer exists. It gives up when the context is done, reporting what it was waiting for.
+func waitForTable(ctx context.Context, dynamo dynamodbiface.DynamoDBAPI, table string, deleted bool) error {
+	pending := fmt.Sprintf("table %q does not exist", table)
+	for {
+		desc, err := dynamo.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{TableName: &table})
+		switch {
+		case hasErrorCode(err, dynamodb.ErrCodeResourceNotFoundException):
+			// A table that was just created may not be visible yet.
+			if deleted {
+				return nil
+			}
+		case err != nil:
+			return err
+		case deleted:
+			pending = fmt.Sprintf("table %q is %s", table, *desc.Table.TableStatus)
+		default:
+			pending = pendingStatus(desc.Table)
+			if pending == "" {
+				return nil
+			}
+		}
+		timer := time.NewTimer(tableWaitDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%s: %w", pending, ctx.Err())
+		}
+	}
+}
+
+// pendingStatus describes the first of the table or its global secondary indexes that is not ACTIVE, or returns an
+// empty string if they all are.
+func pendingStatus(table *dynamodb.TableDescription) string {
+	if status := *table.TableStatus; status != dynamodb.TableStatusActive {
+		return fmt.Sprintf("table %q is %s", *table.TableName, status)
+	}
+	for _, index := range table.GlobalSecondaryIndexes {
+		status := *index.IndexStatus
+		if status == dynamodb.IndexStatusActive {
+			continue
+		}
+		if index.Backfilling != nil && *index.Backfilling {
+			status += " (backfilling)"
+		}
+		return fmt.Sprintf("index %q is %s", *index.IndexName, status)
+	}
+	return ""
+}
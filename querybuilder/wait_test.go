@@ -0,0 +1,158 @@
+package querybuilder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mightyguava/dynamosql/parser"
+	"github.com/mightyguava/dynamosql/schema"
+)
+
+// scriptedTable returns the next of a sequence of table descriptions from each DescribeTable, and fails CreateTable
+// and DeleteTable with the given error. A nil description means the table does not exist. The requests made once a
+// table is created are recorded.
+type scriptedTable struct {
+	dynamodbiface.DynamoDBAPI
+	describes []*dynamodb.TableDescription
+	calls     int
+	err       error
+	ttl       *dynamodb.UpdateTimeToLiveInput
+	tags      *dynamodb.TagResourceInput
+}
+
+func (d *scriptedTable) DescribeTableWithContext(ctx aws.Context, req *dynamodb.DescribeTableInput, opts ...request.Option) (*dynamodb.DescribeTableOutput, error) {
+	desc := d.describes[d.calls]
+	if d.calls < len(d.describes)-1 {
+		d.calls++
+	}
+	if desc == nil {
+		return nil, awserr.New(dynamodb.ErrCodeResourceNotFoundException, "not found", nil)
+	}
+	return &dynamodb.DescribeTableOutput{Table: desc}, nil
+}
+
+func (d *scriptedTable) CreateTableWithContext(ctx aws.Context, req *dynamodb.CreateTableInput, opts ...request.Option) (*dynamodb.CreateTableOutput, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	return &dynamodb.CreateTableOutput{TableDescription: &dynamodb.TableDescription{
+		TableName:   req.TableName,
+		TableArn:    aws.String("arn:aws:dynamodb:us-west-2:123456789012:table/" + *req.TableName),
+		TableStatus: aws.String("CREATING"),
+	}}, nil
+}
+
+func (d *scriptedTable) UpdateTimeToLiveWithContext(ctx aws.Context, req *dynamodb.UpdateTimeToLiveInput, opts ...request.Option) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	d.ttl = req
+	return &dynamodb.UpdateTimeToLiveOutput{}, nil
+}
+
+func (d *scriptedTable) TagResourceWithContext(ctx aws.Context, req *dynamodb.TagResourceInput, opts ...request.Option) (*dynamodb.TagResourceOutput, error) {
+	// Tagging fails until the table exists.
+	if d.calls == 0 {
+		return nil, awserr.New(dynamodb.ErrCodeResourceInUseException, "in use", nil)
+	}
+	d.tags = req
+	return &dynamodb.TagResourceOutput{}, nil
+}
+
+func (d *scriptedTable) DeleteTableWithContext(ctx aws.Context, req *dynamodb.DeleteTableInput, opts ...request.Option) (*dynamodb.DeleteTableOutput, error) {
+	return nil, d.err
+}
+
+func TestWaitForTable(t *testing.T) {
+	defer func(delay time.Duration) { tableWaitDelay = delay }(tableWaitDelay)
+	tableWaitDelay = time.Millisecond
+
+	withIndex := func(tableStatus, indexStatus string) *dynamodb.TableDescription {
+		return &dynamodb.TableDescription{
+			TableName:   aws.String("movies"),
+			TableStatus: aws.String(tableStatus),
+			GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndexDescription{{
+				IndexName:   aws.String("index"),
+				IndexStatus: aws.String(indexStatus),
+				Backfilling: aws.Bool(indexStatus == "CREATING"),
+			}},
+		}
+	}
+	exec := func(t *testing.T, dynamo dynamodbiface.DynamoDBAPI, query string) error {
+		ast, err := parser.Parse(query)
+		require.NoError(t, err)
+		var stmt ExecStmt
+		if ast.CreateTable != nil {
+			stmt, err = PrepareCreateTable(schema.NewTableLoader(nil), ast)
+		} else {
+			stmt, err = PrepareDropTable(schema.NewTableLoader(nil), ast.DropTable)
+		}
+		require.NoError(t, err)
+		_, err = stmt.Do(context.Background(), dynamo, nil)
+		return err
+	}
+
+	t.Run("waits for table and indexes", func(t *testing.T) {
+		dynamo := &scriptedTable{describes: []*dynamodb.TableDescription{
+			nil,
+			withIndex("CREATING", "CREATING"),
+			withIndex("ACTIVE", "CREATING"),
+			withIndex("ACTIVE", "ACTIVE"),
+		}}
+		require.NoError(t, waitForTable(context.Background(), dynamo, "movies", false))
+		require.Equal(t, 3, dynamo.calls)
+	})
+
+	t.Run("waits for deletion", func(t *testing.T) {
+		dynamo := &scriptedTable{describes: []*dynamodb.TableDescription{withIndex("DELETING", "DELETING"), nil}}
+		require.NoError(t, waitForTable(context.Background(), dynamo, "movies", true))
+		require.Equal(t, 1, dynamo.calls)
+	})
+
+	t.Run("respects context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		dynamo := &scriptedTable{describes: []*dynamodb.TableDescription{withIndex("ACTIVE", "CREATING")}}
+		err := waitForTable(ctx, dynamo, "movies", false)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.EqualError(t, err, `index "index" is CREATING (backfilling): context deadline exceeded`)
+	})
+
+	t.Run("create table if not exists", func(t *testing.T) {
+		dynamo := &scriptedTable{
+			describes: []*dynamodb.TableDescription{withIndex("ACTIVE", "ACTIVE")},
+			err:       awserr.New(dynamodb.ErrCodeResourceInUseException, "in use", nil),
+		}
+		query := `CREATE TABLE IF NOT EXISTS movies (title STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WITH (TTL = expires_at) WAIT`
+		require.NoError(t, exec(t, dynamo, query))
+		err := exec(t, dynamo, `CREATE TABLE movies (title STRING HASH KEY) BILLING MODE PAY_PER_REQUEST`)
+		require.True(t, hasErrorCode(err, dynamodb.ErrCodeResourceInUseException))
+	})
+
+	t.Run("create table with options", func(t *testing.T) {
+		dynamo := &scriptedTable{describes: []*dynamodb.TableDescription{withIndex("CREATING", "CREATING"), withIndex("ACTIVE", "ACTIVE")}}
+		query := `CREATE TABLE movies (title STRING HASH KEY) BILLING MODE PAY_PER_REQUEST WITH (TTL = expires_at, TAGS = {team: 'payments'})`
+		require.NoError(t, exec(t, dynamo, query))
+		require.Equal(t, aws.String("expires_at"), dynamo.ttl.TimeToLiveSpecification.AttributeName)
+		require.Equal(t, &dynamodb.TagResourceInput{
+			ResourceArn: aws.String("arn:aws:dynamodb:us-west-2:123456789012:table/movies"),
+			Tags:        []*dynamodb.Tag{{Key: aws.String("team"), Value: aws.String("payments")}},
+		}, dynamo.tags)
+	})
+
+	t.Run("drop table if exists", func(t *testing.T) {
+		dynamo := &scriptedTable{
+			describes: []*dynamodb.TableDescription{nil},
+			err:       awserr.New(dynamodb.ErrCodeResourceNotFoundException, "not found", nil),
+		}
+		require.NoError(t, exec(t, dynamo, `DROP TABLE IF EXISTS movies WAIT`))
+		err := exec(t, dynamo, `DROP TABLE movies`)
+		require.True(t, hasErrorCode(err, dynamodb.ErrCodeResourceNotFoundException))
+	})
+}
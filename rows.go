@@ -1,6 +1,7 @@
 package dynamosql
 
 import (
+	"context"
 	"database/sql/driver"
 	"fmt"
 	"io"
@@ -8,16 +9,151 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/mightyguava/dynamosql/parser"
 )
 
+// page is a page of items returned by a Query or Scan.
+type page struct {
+	Items            []map[string]*dynamodb.AttributeValue
+	LastEvaluatedKey map[string]*dynamodb.AttributeValue
+}
+
+// fetchPage fetches the page of items that begins after startKey.
+type fetchPage func(ctx context.Context, startKey map[string]*dynamodb.AttributeValue) (*page, error)
+
+// paginate returns a function that fetches the next non-empty page of items, or io.EOF if there are no more.
+func paginate(ctx context.Context, fetch fetchPage) func(lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*page, error) {
+	return func(lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*page, error) {
+		for lastEvaluatedKey != nil {
+			resp, err := fetch(ctx, lastEvaluatedKey)
+			if err != nil {
+				return nil, err
+			}
+			if len(resp.Items) > 0 {
+				return resp, nil
+			}
+			// An empty response does not necessarily indicate there are no more results. It's possible the
+			// filter expression filtered out all values in this range. Need to keep paging until LastEvaluatedKey
+			// is nil.
+			lastEvaluatedKey = resp.LastEvaluatedKey
+		}
+		return nil, io.EOF
+	}
+}
+
+// maxConcurrentFetches is the most fetches that mergePages and concatPages run at once, however many segments or
+// partitions there are. The others start as running fetches finish.
+var maxConcurrentFetches = 16
+
+// mergePages concurrently pages through all results of each fetch, and merges the pages into a single stream.
+// Each fetch may buffer at most one page ahead of the reader.
+//
+// The returned function ignores lastEvaluatedKey, and returns the next page from any of the fetches, or io.EOF once
+// all of them are exhausted. If any fetch fails, the remaining fetches are cancelled and the first error is returned
+// after the buffered pages have been read. Cancel ctx to stop all fetches early.
+func mergePages(ctx context.Context, fetches []fetchPage) func(lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*page, error) {
+	pages := make(chan *page, maxConcurrentFetches)
+	var err error
+	go func() {
+		err = runFetches(ctx, fetches, func(ctx context.Context, i int, fetch fetchPage) error {
+			return sendPages(ctx, fetch, pages)
+		})
+		close(pages)
+	}()
+	return func(map[string]*dynamodb.AttributeValue) (*page, error) {
+		resp, ok := <-pages
+		if ok {
+			return resp, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+}
+
+// concatPages is like mergePages, but returns all pages of each fetch before moving on to the next, as if the
+// fetches were run one after another. The fetches still run concurrently, each buffering at most one page ahead.
+func concatPages(ctx context.Context, fetches []fetchPage) func(lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*page, error) {
+	chans := make([]chan *page, len(fetches))
+	for i := range chans {
+		chans[i] = make(chan *page, 1)
+	}
+	var err error
+	done := make(chan struct{})
+	go func() {
+		err = runFetches(ctx, fetches, func(ctx context.Context, i int, fetch fetchPage) error {
+			defer close(chans[i])
+			return sendPages(ctx, fetch, chans[i])
+		})
+		close(done)
+	}()
+	unread := chans
+	return func(map[string]*dynamodb.AttributeValue) (*page, error) {
+		for len(unread) > 0 {
+			resp, ok := <-unread[0]
+			if ok {
+				return resp, nil
+			}
+			unread = unread[1:]
+		}
+		<-done
+		if err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+}
+
+// runFetches calls run for each fetch in order, with at most maxConcurrentFetches running at once, and returns the
+// first error. The context passed to run is cancelled once any of them fails.
+func runFetches(ctx context.Context, fetches []fetchPage, run func(ctx context.Context, i int, fetch fetchPage) error) error {
+	g, ctx := errgroup.WithContext(ctx)
+	// Each fetch takes a slot before it starts, and frees it once it is done.
+	running := make(chan struct{}, maxConcurrentFetches)
+	for i, fetch := range fetches {
+		i, fetch := i, fetch
+		running <- struct{}{}
+		g.Go(func() error {
+			defer func() { <-running }()
+			return run(ctx, i, fetch)
+		})
+	}
+	return g.Wait()
+}
+
+// sendPages pages through all results of fetch, sending the non-empty pages to the channel.
+func sendPages(ctx context.Context, fetch fetchPage, pages chan<- *page) error {
+	var startKey map[string]*dynamodb.AttributeValue
+	for {
+		resp, err := fetch(ctx, startKey)
+		if err != nil {
+			return err
+		}
+		if len(resp.Items) > 0 {
+			select {
+			case pages <- resp:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if resp.LastEvaluatedKey == nil {
+			return nil
+		}
+		startKey = resp.LastEvaluatedKey
+	}
+}
+
 type rows struct {
-	resp        *dynamodb.QueryOutput
-	nextPage    func(lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*dynamodb.QueryOutput, error)
+	resp        *page
+	nextPage    func(lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*page, error)
 	cols        []*parser.ProjectionColumn
 	mapToGoType bool
 	limit       int
+	// If set, called when the rows are closed to release resources used by nextPage.
+	close func()
 
 	nextRow int
 	count   int
@@ -45,6 +181,9 @@ func (r *rows) Columns() []string {
 }
 
 func (r *rows) Close() error {
+	if r.close != nil {
+		r.close()
+	}
 	return nil
 }
 
@@ -213,3 +352,66 @@ func (o *oneRow) Next(dest []driver.Value) error {
 }
 
 var _ driver.Rows = &oneRow{}
+
+// staticRows returns rows of driver values that are already in memory.
+type staticRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (s *staticRows) Columns() []string {
+	return s.columns
+}
+
+func (s *staticRows) Close() error {
+	return nil
+}
+
+func (s *staticRows) Next(dest []driver.Value) error {
+	if len(s.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, s.rows[0])
+	s.rows = s.rows[1:]
+	return nil
+}
+
+var _ driver.Rows = &staticRows{}
+
+// resultSets returns the rows of several statements as consecutive result sets.
+type resultSets struct {
+	sets []driver.Rows
+}
+
+func (r *resultSets) Columns() []string {
+	return r.sets[0].Columns()
+}
+
+func (r *resultSets) Close() error {
+	var err error
+	for _, rows := range r.sets {
+		if cerr := rows.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (r *resultSets) Next(dest []driver.Value) error {
+	return r.sets[0].Next(dest)
+}
+
+func (r *resultSets) HasNextResultSet() bool {
+	return len(r.sets) > 1
+}
+
+func (r *resultSets) NextResultSet() error {
+	if len(r.sets) <= 1 {
+		return io.EOF
+	}
+	err := r.sets[0].Close()
+	r.sets = r.sets[1:]
+	return err
+}
+
+var _ driver.RowsNextResultSet = &resultSets{}
@@ -1,10 +1,16 @@
 package dynamosql
 
 import (
+	"context"
 	"database/sql/driver"
+	"errors"
+	"fmt"
 	"io"
+	"sort"
 	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/alecthomas/participle"
 	"github.com/aws/aws-sdk-go/aws"
@@ -16,12 +22,12 @@ import (
 
 func TestRows_PaginatesAndAppliesLimit(t *testing.T) {
 	next := 0
-	nextPage := func(lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*dynamodb.QueryOutput, error) {
+	nextPage := func(lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*page, error) {
 		a := next
 		next++
 		b := next
 		next++
-		return &dynamodb.QueryOutput{
+		return &page{
 			Items: []map[string]*dynamodb.AttributeValue{
 				{
 					"id": {
@@ -172,3 +178,158 @@ func TestPluck(t *testing.T) {
 		})
 	}
 }
+
+func TestMergePages(t *testing.T) {
+	// Each fetch returns 3 pages of 2 items.
+	fetcher := func(segment int, fail bool) fetchPage {
+		return func(ctx context.Context, startKey map[string]*dynamodb.AttributeValue) (*page, error) {
+			n := 0
+			if startKey != nil {
+				n, _ = strconv.Atoi(*startKey["n"].N)
+			}
+			if fail && n == 2 {
+				return nil, errors.New("segment failed")
+			}
+			resp := &page{}
+			for i := n * 2; i < n*2+2; i++ {
+				resp.Items = append(resp.Items, map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String(fmt.Sprintf("%d-%d", segment, i))},
+				})
+			}
+			if n < 2 {
+				resp.LastEvaluatedKey = map[string]*dynamodb.AttributeValue{"n": {N: aws.String(strconv.Itoa(n + 1))}}
+			}
+			return resp, nil
+		}
+	}
+	readAll := func(r *rows) ([]string, error) {
+		var ids []string
+		row := make([]driver.Value, 1)
+		for {
+			if err := r.Next(row); err != nil {
+				return ids, err
+			}
+			ids = append(ids, row[0].(string))
+		}
+	}
+	cols := []*parser.ProjectionColumn{{DocumentPath: &parser.DocumentPath{Fragment: []*parser.PathFragment{{Symbol: "id"}}}}}
+
+	t.Run("merges all pages", func(t *testing.T) {
+		r := &rows{
+			resp:     &page{},
+			nextPage: mergePages(context.Background(), []fetchPage{fetcher(0, false), fetcher(1, false), fetcher(2, false)}),
+			cols:     cols,
+		}
+		ids, err := readAll(r)
+		require.Equal(t, io.EOF, err)
+		require.Len(t, ids, 18)
+		sort.Strings(ids)
+		require.Equal(t, []string{"0-0", "0-1", "0-2", "0-3", "0-4", "0-5"}, ids[:6])
+	})
+
+	t.Run("reports first error", func(t *testing.T) {
+		r := &rows{
+			resp:     &page{},
+			nextPage: mergePages(context.Background(), []fetchPage{fetcher(0, false), fetcher(1, true)}),
+			cols:     cols,
+		}
+		_, err := readAll(r)
+		require.EqualError(t, err, "segment failed")
+	})
+
+	t.Run("applies limit and stops on close", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		r := &rows{
+			resp:     &page{},
+			nextPage: mergePages(ctx, []fetchPage{fetcher(0, false), fetcher(1, false)}),
+			cols:     cols,
+			limit:    3,
+			close:    cancel,
+		}
+		ids, err := readAll(r)
+		require.Equal(t, io.EOF, err)
+		require.Len(t, ids, 3)
+		require.NoError(t, r.Close())
+		require.Error(t, ctx.Err())
+	})
+
+	t.Run("concatenates pages in fetch order", func(t *testing.T) {
+		r := &rows{
+			resp:     &page{},
+			nextPage: concatPages(context.Background(), []fetchPage{fetcher(1, false), fetcher(0, false)}),
+			cols:     cols,
+			limit:    8,
+		}
+		ids, err := readAll(r)
+		require.Equal(t, io.EOF, err)
+		require.Equal(t, []string{"1-0", "1-1", "1-2", "1-3", "1-4", "1-5", "0-0", "0-1"}, ids)
+	})
+
+	t.Run("concatenation reports first error", func(t *testing.T) {
+		r := &rows{
+			resp:     &page{},
+			nextPage: concatPages(context.Background(), []fetchPage{fetcher(0, false), fetcher(1, true)}),
+			cols:     cols,
+		}
+		_, err := readAll(r)
+		require.EqualError(t, err, "segment failed")
+	})
+
+	t.Run("bounds concurrent fetches", func(t *testing.T) {
+		defer func(limit int) { maxConcurrentFetches = limit }(maxConcurrentFetches)
+		maxConcurrentFetches = 2
+		var running, maxRunning int32
+		counted := func(fetch fetchPage) fetchPage {
+			return func(ctx context.Context, startKey map[string]*dynamodb.AttributeValue) (*page, error) {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					max := atomic.LoadInt32(&maxRunning)
+					if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				return fetch(ctx, startKey)
+			}
+		}
+		var fetches []fetchPage
+		for i := 0; i < 5; i++ {
+			fetches = append(fetches, counted(fetcher(i, false)))
+		}
+		for _, pages := range []func(context.Context, []fetchPage) func(map[string]*dynamodb.AttributeValue) (*page, error){
+			mergePages,
+			concatPages,
+		} {
+			ids, err := readAll(&rows{resp: &page{}, nextPage: pages(context.Background(), fetches), cols: cols})
+			require.Equal(t, io.EOF, err)
+			require.Len(t, ids, 30)
+		}
+		require.True(t, atomic.LoadInt32(&maxRunning) <= 2, "%d fetches ran at once", maxRunning)
+	})
+}
+
+func TestRows_ResultSets(t *testing.T) {
+	r := &resultSets{sets: []driver.Rows{
+		&staticRows{columns: []string{"a"}, rows: [][]driver.Value{{1}, {2}}},
+		&staticRows{columns: []string{"b", "c"}, rows: [][]driver.Value{{"x", "y"}}},
+	}}
+	var got [][]driver.Value
+	for {
+		cols := r.Columns()
+		for {
+			row := make([]driver.Value, len(cols))
+			if err := r.Next(row); err == io.EOF {
+				break
+			}
+			got = append(got, row)
+		}
+		if !r.HasNextResultSet() {
+			break
+		}
+		require.NoError(t, r.NextResultSet())
+	}
+	require.Equal(t, [][]driver.Value{{1}, {2}, {"x", "y"}}, got)
+	require.Equal(t, io.EOF, r.NextResultSet())
+	require.NoError(t, r.Close())
+}
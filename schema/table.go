@@ -165,3 +165,9 @@ func (l *TableLoader) Get(ctx context.Context, name string) (*Table, error) {
 		return result.Val.(*Table), nil
 	}
 }
+
+// Invalidate drops the cached schema of a table, so that it is loaded again on the next Get. Call it after changing
+// the table's schema.
+func (l *TableLoader) Invalidate(name string) {
+	l.tables.Delete(name)
+}
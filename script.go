@@ -0,0 +1,103 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/mightyguava/dynamosql/parser"
+)
+
+// scriptStmt runs the statements of a script in order. Each statement is prepared just before it runs, so that it
+// can use the tables created by the statements before it.
+type scriptStmt struct {
+	legacyStmtMixin
+	conn       *conn
+	statements []*parser.ScriptStatement
+}
+
+var _ fullStmt = &scriptStmt{}
+
+var errScriptArgs = errors.New("args cannot be bound to a script of several statements")
+
+// ExecContext runs the statements, and reports the rows affected by all of them.
+func (s *scriptStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if len(args) > 0 {
+		return nil, errScriptArgs
+	}
+	var affected int64
+	for i := range s.statements {
+		stmt, err := s.prepare(ctx, i)
+		if err != nil {
+			return nil, err
+		}
+		result, err := stmt.ExecContext(ctx, nil)
+		if err != nil {
+			return nil, s.wrap(i, err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return nil, s.wrap(i, err)
+		}
+		affected += n
+	}
+	return scriptResult(affected), nil
+}
+
+// QueryContext runs the statements, and returns a result set for each statement that returns rows.
+func (s *scriptStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if len(args) > 0 {
+		return nil, errScriptArgs
+	}
+	sets := &resultSets{}
+	for i := range s.statements {
+		stmt, err := s.prepare(ctx, i)
+		if err != nil {
+			_ = sets.Close()
+			return nil, err
+		}
+		switch stmt.(type) {
+		case *queryStmt, *showStmt:
+			rows, err := stmt.QueryContext(ctx, nil)
+			if err != nil {
+				_ = sets.Close()
+				return nil, s.wrap(i, err)
+			}
+			sets.sets = append(sets.sets, rows)
+		default:
+			if _, err := stmt.ExecContext(ctx, nil); err != nil {
+				_ = sets.Close()
+				return nil, s.wrap(i, err)
+			}
+		}
+	}
+	if len(sets.sets) == 0 {
+		return &staticRows{}, nil
+	}
+	return sets, nil
+}
+
+func (s *scriptStmt) prepare(ctx context.Context, i int) (fullStmt, error) {
+	stmt, err := s.conn.prepare(ctx, s.statements[i].AST)
+	if err != nil {
+		return nil, s.wrap(i, err)
+	}
+	return stmt.(fullStmt), nil
+}
+
+// wrap names the statement that failed, since the script may have many statements that look alike.
+func (s *scriptStmt) wrap(i int, err error) error {
+	return fmt.Errorf("statement %d (line %d): %w", i+1, s.statements[i].Pos.Line, err)
+}
+
+// scriptResult is the number of rows affected by the statements of a script.
+type scriptResult int64
+
+func (r scriptResult) LastInsertId() (int64, error) {
+	return 0, errors.New("LastInsertId is not supported")
+}
+
+func (r scriptResult) RowsAffected() (int64, error) {
+	return int64(r), nil
+}
@@ -14,25 +14,42 @@ import (
 
 type execStmt struct {
 	legacyStmtMixin
+	conn         *conn
 	preparedStmt querybuilder.ExecStmt
 	dynamo       dynamodbiface.DynamoDBAPI
 	mapToGoType  bool
 }
 
 func (s *execStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
-	return s.preparedStmt.Do(ctx, s.dynamo, args)
+	return s.do(ctx, args)
 }
 
 func (s *execStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-	result, err := s.preparedStmt.Do(ctx, s.dynamo, args)
+	result, err := s.do(ctx, args)
 	if err != nil {
 		return nil, err
 	}
 	return &oneRow{item: result.Item()}, nil
 }
 
+// do executes the statement, or buffers it if the conn is in a transaction.
+func (s *execStmt) do(ctx context.Context, args []driver.NamedValue) (*querybuilder.DriverResult, error) {
+	if s.conn.tx == nil {
+		return s.preparedStmt.Do(ctx, s.dynamo, args)
+	}
+	if s.conn.tx.reads != nil {
+		return nil, errors.New("only SELECT may be used in a read-only transaction")
+	}
+	stmt, ok := s.preparedStmt.(querybuilder.TransactStmt)
+	if !ok {
+		return nil, errors.New("statement cannot be executed in a transaction")
+	}
+	return stmt.Transact(s.conn.tx.writes, args)
+}
+
 type queryStmt struct {
 	legacyStmtMixin
+	conn         *conn
 	preparedStmt *querybuilder.PreparedQuery
 	dynamo       dynamodbiface.DynamoDBAPI
 	mapToGoType  bool
@@ -44,42 +61,212 @@ func (s *queryStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (
 
 func (s *queryStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
 	q := s.preparedStmt
-	req, err := q.NewRequest(args)
+	if s.conn.tx != nil && s.conn.tx.reads != nil {
+		return s.readInTx(ctx, args)
+	}
+	if q.Scan != nil && q.Scan.TotalSegments != nil {
+		return s.parallelScan(ctx, args)
+	}
+	if q.Partitions != nil {
+		return s.queryPartitions(ctx, args)
+	}
+	if q.BatchGet != nil {
+		return s.batchGet(ctx, args)
+	}
+	fetch, err := s.fetcher(args)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := s.dynamo.QueryWithContext(ctx, req)
+	resp, err := fetch(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 	return &rows{
-		nextPage: func(lastEvaluatedKey map[string]*dynamodb.AttributeValue) (*dynamodb.QueryOutput, error) {
-			for lastEvaluatedKey != nil {
-				req.ExclusiveStartKey = lastEvaluatedKey
-				// nolint: govet
-				resp, err := s.dynamo.QueryWithContext(ctx, req)
-				if err != nil {
-					return nil, err
-				}
-				if len(resp.Items) > 0 {
-					return resp, nil
-				}
-				// An empty response does not necessarily indicate there are no more results. It's possible the
-				// filter expression filtered out all values in this range. Need to keep paging until LastEvaluatedKey
-				// is nil.
-				if resp.LastEvaluatedKey != nil {
-					lastEvaluatedKey = resp.LastEvaluatedKey
-				}
+		nextPage:    paginate(ctx, fetch),
+		cols:        q.Columns,
+		resp:        resp,
+		mapToGoType: s.mapToGoType,
+		limit:       q.Limit,
+	}, nil
+}
+
+// readInTx adds the reads to the read-only transaction. The reads are made when the rows are first read.
+func (s *queryStmt) readInTx(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	q := s.preparedStmt
+	read, err := s.conn.tx.reads.Read(q, args)
+	if err != nil {
+		return nil, err
+	}
+	done := false
+	return &rows{
+		nextPage: func(map[string]*dynamodb.AttributeValue) (*page, error) {
+			if done {
+				return nil, io.EOF
+			}
+			done = true
+			items, err := read(ctx, s.dynamo)
+			if err != nil {
+				return nil, err
 			}
-			return nil, io.EOF
+			if len(items) == 0 {
+				return nil, io.EOF
+			}
+			return &page{Items: items}, nil
 		},
 		cols:        q.Columns,
-		resp:        resp,
+		resp:        &page{},
+		mapToGoType: s.mapToGoType,
+		limit:       q.Limit,
+	}, nil
+}
+
+// parallelScan runs a worker for each segment of the scan, and merges their results.
+func (s *queryStmt) parallelScan(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	q := s.preparedStmt
+	reqs, err := q.NewScanRequests(args)
+	if err != nil {
+		return nil, err
+	}
+	fetches := make([]fetchPage, len(reqs))
+	for i, req := range reqs {
+		fetches[i] = s.scan(req)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &rows{
+		nextPage:    mergePages(ctx, fetches),
+		close:       cancel,
+		cols:        q.Columns,
+		resp:        &page{},
+		mapToGoType: s.mapToGoType,
+		limit:       q.Limit,
+	}, nil
+}
+
+// queryPartitions runs a Query for each partition key value concurrently. The results are returned one partition
+// after another, so that the items of each partition stay in sort key order.
+func (s *queryStmt) queryPartitions(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	q := s.preparedStmt
+	reqs, err := q.NewRequests(args)
+	if err != nil {
+		return nil, err
+	}
+	fetches := make([]fetchPage, len(reqs))
+	for i, req := range reqs {
+		fetches[i] = s.query(req)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &rows{
+		nextPage:    concatPages(ctx, fetches),
+		close:       cancel,
+		cols:        q.Columns,
+		resp:        &page{},
 		mapToGoType: s.mapToGoType,
 		limit:       q.Limit,
 	}, nil
 }
 
+// batchGet gets the items in chunks using BatchGetItem. The chunks are fetched concurrently.
+func (s *queryStmt) batchGet(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	q := s.preparedStmt
+	reqs, err := q.NewBatchGetRequests(args)
+	if err != nil {
+		return nil, err
+	}
+	fetches := make([]fetchPage, len(reqs))
+	for i, req := range reqs {
+		req := req
+		fetches[i] = func(ctx context.Context, _ map[string]*dynamodb.AttributeValue) (*page, error) {
+			items, err := querybuilder.BatchGet(ctx, s.dynamo, req)
+			if err != nil {
+				return nil, err
+			}
+			return &page{Items: items}, nil
+		}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &rows{
+		nextPage:    concatPages(ctx, fetches),
+		close:       cancel,
+		cols:        q.Columns,
+		resp:        &page{},
+		mapToGoType: s.mapToGoType,
+		limit:       q.Limit,
+	}, nil
+}
+
+// fetcher binds the args and returns a function to fetch pages of the Query, Scan or GetItem.
+func (s *queryStmt) fetcher(args []driver.NamedValue) (fetchPage, error) {
+	q := s.preparedStmt
+	if q.GetItem != nil {
+		req, err := q.NewGetItemRequest(args)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, _ map[string]*dynamodb.AttributeValue) (*page, error) {
+			resp, err := s.dynamo.GetItemWithContext(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			if resp.Item == nil {
+				return &page{}, nil
+			}
+			return &page{Items: []map[string]*dynamodb.AttributeValue{resp.Item}}, nil
+		}, nil
+	}
+	if q.Scan != nil {
+		req, err := q.NewScanRequest(args)
+		if err != nil {
+			return nil, err
+		}
+		return s.scan(req), nil
+	}
+	req, err := q.NewRequest(args)
+	if err != nil {
+		return nil, err
+	}
+	return s.query(req), nil
+}
+
+func (s *queryStmt) query(req *dynamodb.QueryInput) fetchPage {
+	return func(ctx context.Context, startKey map[string]*dynamodb.AttributeValue) (*page, error) {
+		req.ExclusiveStartKey = startKey
+		resp, err := s.dynamo.QueryWithContext(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return &page{Items: resp.Items, LastEvaluatedKey: resp.LastEvaluatedKey}, nil
+	}
+}
+
+func (s *queryStmt) scan(req *dynamodb.ScanInput) fetchPage {
+	return func(ctx context.Context, startKey map[string]*dynamodb.AttributeValue) (*page, error) {
+		req.ExclusiveStartKey = startKey
+		resp, err := s.dynamo.ScanWithContext(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return &page{Items: resp.Items, LastEvaluatedKey: resp.LastEvaluatedKey}, nil
+	}
+}
+
+type showStmt struct {
+	legacyStmtMixin
+	preparedStmt querybuilder.ShowStmt
+	dynamo       dynamodbiface.DynamoDBAPI
+}
+
+func (s *showStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return nil, errors.New("called Exec() on a statement that returns rows, use Query() instead")
+}
+
+func (s *showStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	result, err := s.preparedStmt.Show(ctx, s.dynamo)
+	if err != nil {
+		return nil, err
+	}
+	return &staticRows{columns: result.Columns, rows: result.Rows}, nil
+}
+
 // wrapper type just for compile time type checking.
 type fullStmt interface {
 	driver.Stmt
@@ -90,6 +277,7 @@ type fullStmt interface {
 var (
 	_ fullStmt = &execStmt{}
 	_ fullStmt = &queryStmt{}
+	_ fullStmt = &showStmt{}
 )
 
 // mixin to provide no-op/panic implementations of useless db/sql methods
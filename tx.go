@@ -0,0 +1,34 @@
+package dynamosql
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/mightyguava/dynamosql/querybuilder"
+)
+
+// tx buffers the writes executed on its conn, and submits them together on Commit. Reads are not part of the
+// transaction, and do not see its buffered writes.
+//
+// A read-only tx instead collects point reads, and makes them together so that they are consistent.
+type tx struct {
+	conn   *conn
+	ctx    context.Context
+	writes *querybuilder.Transaction
+	reads  *querybuilder.ReadTransaction
+}
+
+var _ driver.Tx = &tx{}
+
+func (t *tx) Commit() error {
+	t.conn.tx = nil
+	if t.writes == nil {
+		return nil
+	}
+	return t.writes.Commit(t.ctx, t.conn.dynamo)
+}
+
+func (t *tx) Rollback() error {
+	t.conn.tx = nil
+	return nil
+}